@@ -147,7 +147,7 @@ func init() {
 
 // initializeConfig creates a default configuration file
 func initializeConfig(dir string) error {
-	cfg := config.DefaultConfig()
+	cfg := config.DefaultConfigFor(dir)
 	return cfg.Save(dir)
 }
 
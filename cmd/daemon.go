@@ -9,6 +9,8 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -34,9 +36,23 @@ type daemonStatusResponse struct {
 	Status    string                             `json:"status"`
 	Directory string                             `json:"directory"`
 	Timestamp string                             `json:"timestamp"`
+	RunID     string                             `json:"run_id"`
 	Commands  map[string]statusCommandResult    `json:"commands"`
 }
 
+// daemonRunSummary describes a run in the /runs listing
+type daemonRunSummary struct {
+	ID           string                         `json:"id"`
+	Trigger      runner.Trigger                 `json:"trigger"`
+	ChangedFiles []string                       `json:"changed_files,omitempty"`
+	GitSHA       string                         `json:"git_sha,omitempty"`
+	Dirty        bool                           `json:"dirty,omitempty"`
+	Timestamp    string                         `json:"timestamp"`
+	Duration     string                         `json:"duration"`
+	Passed       bool                           `json:"passed"`
+	Statuses     map[string]runner.ResultStatus `json:"statuses"`
+}
+
 var daemonCmd = &cobra.Command{
 	Use:   "daemon [directory]",
 	Short: "Start background daemon server",
@@ -47,6 +63,11 @@ The daemon runs in the background and provides endpoints for:
 - GET /status/compact - Get compact one-line status
 - POST /run - Force a manual run of all commands
 - GET /history - Get command execution history
+- GET /runs - List recent runs; GET /runs/{id} - Get one run with its results
+- GET /stream - Stream command output live (server-sent events)
+- GET /metrics - CPU time, peak memory and duration per command (Prometheus format)
+- GET /trends - Duration and issue trends with regressions (JSON)
+- GET /events - Stream state changes and regressions (server-sent events)
 
 Examples:
   kwatch daemon                        # Start daemon on port 3737
@@ -88,6 +109,11 @@ Examples:
 
 		r := runner.NewRunner(runnerConfig, kwatchConfig)
 		
+		// Log state changes and regressions as they happen
+		r.SubscribeEvents(func(event runner.Event) {
+			log.Printf("[%s] %s\n", event.Kind, event.Message)
+		})
+		
 		// Create daemon server
 		daemon := &daemonServer{
 			runner:  r,
@@ -127,6 +153,11 @@ Examples:
 		fmt.Printf("  GET  http://%s/status/compact\n", addr)
 		fmt.Printf("  POST http://%s/run\n", addr)
 		fmt.Printf("  GET  http://%s/history\n", addr)
+		fmt.Printf("  GET  http://%s/runs\n", addr)
+		fmt.Printf("  GET  http://%s/stream\n", addr)
+		fmt.Printf("  GET  http://%s/metrics\n", addr)
+		fmt.Printf("  GET  http://%s/trends\n", addr)
+		fmt.Printf("  GET  http://%s/events\n", addr)
 		fmt.Printf("  GET  http://%s/health\n", addr)
 		fmt.Printf("\nPress Ctrl+C to stop the daemon\n")
 		fmt.Printf("===============================\n\n")
@@ -159,8 +190,24 @@ func (d *daemonServer) setupRoutes() *http.ServeMux {
 	// History endpoint
 	mux.HandleFunc("/history", d.handleHistory)
 	
+	// Runs endpoints
+	mux.HandleFunc("/runs", d.handleRuns)
+	mux.HandleFunc("/runs/", d.handleRunByID)
+	
+	// Live output endpoint (server-sent events)
+	mux.HandleFunc("/stream", d.handleStream)
+	
 	// Health check endpoint
 	mux.HandleFunc("/health", d.handleHealth)
+	
+	// Resource usage metrics (Prometheus text format)
+	mux.HandleFunc("/metrics", d.handleMetrics)
+	
+	// Duration and issue trends
+	mux.HandleFunc("/trends", d.handleTrends)
+	
+	// State change and regression alerts (server-sent events)
+	mux.HandleFunc("/events", d.handleEvents)
 
 	return mux
 }
@@ -173,32 +220,35 @@ func (d *daemonServer) handleStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
-	results := d.runner.RunAll(ctx)
+	run := d.runner.RunAll(ctx, runner.TriggerAPI)
+	results := run.Commands
 
 	response := daemonStatusResponse{
 		Status:    "ok",
 		Directory: d.workDir,
 		Timestamp: time.Now().Format(time.RFC3339),
+		RunID:     run.ID,
 		Commands:  make(map[string]statusCommandResult),
 	}
 
 	// Convert results to response format
-	cmdNames := map[runner.CommandType]string{
-		runner.TypescriptCheck: "tsc",
-		runner.LintCheck:       "lint",
-		runner.TestRunner:      "test",
-	}
-
 	for cmdType, result := range results {
-		cmdName := cmdNames[cmdType]
-		if cmdName == "" {
-			cmdName = string(cmdType)
-		}
+		cmdName := cmdType.ShortName()
 
 		response.Commands[cmdName] = statusCommandResult{
-			Passed:     result.Passed,
-			IssueCount: result.IssueCount,
-			Duration:   formatDuration(result.Duration),
+			Passed:        result.Passed,
+			Status:        string(result.State()),
+			IssueCount:    result.IssueCount,
+			FileCount:     result.FileCount,
+			ErrorCount:    result.ErrorCount,
+			WarningCount:  result.WarningCount,
+			FixableCount:  result.FixableCount,
+			Duration:      formatDuration(result.Duration),
+			Cached:        result.Cached,
+			Coverage:      result.Coverage,
+			Attempts:      result.Attempts,
+			LimitExceeded: result.LimitExceeded,
+			Diagnostics:   result.Diagnostics,
 		}
 	}
 
@@ -214,8 +264,8 @@ func (d *daemonServer) handleStatusCompact(w http.ResponseWriter, r *http.Reques
 	}
 
 	ctx := context.Background()
-	results := d.runner.RunAll(ctx)
-	compact := runner.FormatCompactStatus(results)
+	run := d.runner.RunAll(ctx, runner.TriggerAPI)
+	compact := runner.FormatCompactStatus(run.Commands)
 
 	w.Header().Set("Content-Type", "text/plain")
 	w.Write([]byte(compact))
@@ -229,12 +279,13 @@ func (d *daemonServer) handleRun(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := context.Background()
-	results := d.runner.RunAll(ctx)
+	run := d.runner.RunAll(ctx, runner.TriggerAPI)
 
 	response := map[string]interface{}{
 		"status":    "completed",
 		"timestamp": time.Now().Format(time.RFC3339),
-		"results":   results,
+		"run_id":    run.ID,
+		"results":   run.Commands,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -259,6 +310,196 @@ func (d *daemonServer) handleHistory(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// handleRuns handles GET /runs, listing recent runs newest first. An
+// optional ?limit= query parameter caps the number of runs (default 20).
+func (d *daemonServer) handleRuns(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 20
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	runs := d.runner.Runs()
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+
+	summaries := make([]daemonRunSummary, len(runs))
+	for i, run := range runs {
+		statuses := make(map[string]runner.ResultStatus, len(run.Commands))
+		for cmdType, result := range run.Commands {
+			statuses[string(cmdType)] = result.State()
+		}
+		summaries[i] = daemonRunSummary{
+			ID:           run.ID,
+			Trigger:      run.Trigger,
+			ChangedFiles: run.ChangedFiles,
+			GitSHA:       run.GitSHA,
+			Dirty:        run.Dirty,
+			Timestamp:    run.Timestamp.Format(time.RFC3339),
+			Duration:     formatDuration(run.Duration),
+			Passed:       run.Passed(),
+			Statuses:     statuses,
+		}
+	}
+
+	response := map[string]interface{}{
+		"runs":  summaries,
+		"count": len(summaries),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleRunByID handles GET /runs/{id}, returning a run with its results
+func (d *daemonServer) handleRunByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	run, exists := d.runner.Run(strings.TrimPrefix(r.URL.Path, "/runs/"))
+	if !exists {
+		http.Error(w, "Run not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
+
+// handleStream handles GET /stream, sending command output lines as
+// server-sent events while commands run. An optional ?command= query
+// parameter limits the stream to one command type.
+func (d *daemonServer) handleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	// The stream outlives the server's write timeout
+	controller.SetWriteDeadline(time.Time{})
+
+	filter := commandTypeForName(r.URL.Query().Get("command"))
+	lines := make(chan runner.OutputLine, 256)
+	unsubscribe := d.runner.Subscribe(func(line runner.OutputLine) {
+		if filter != "" && line.Command != filter {
+			return
+		}
+		// Drop lines rather than stall the command on a slow client
+		select {
+		case lines <- line:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	controller.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case line := <-lines:
+			data, err := json.Marshal(line)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: output\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			if err := controller.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleTrends handles GET /trends
+func (d *daemonServer) handleTrends(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	trends := d.runner.Trends()
+	
+	response := map[string]interface{}{
+		"trends":      trends,
+		"regressions": trendRegressions(trends),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleEvents handles GET /events, sending state changes and regressions
+// as server-sent events. An optional ?command= query parameter limits the
+// stream to one command type.
+func (d *daemonServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	// The stream outlives the server's write timeout
+	controller.SetWriteDeadline(time.Time{})
+
+	filter := commandTypeForName(r.URL.Query().Get("command"))
+	events := make(chan runner.Event, 64)
+	unsubscribe := d.runner.SubscribeEvents(func(event runner.Event) {
+		if filter != "" && event.Command != filter {
+			return
+		}
+		// Drop events rather than stall the command on a slow client
+		select {
+		case events <- event:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	controller.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Kind, data); err != nil {
+				return
+			}
+			if err := controller.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
 // handleHealth handles GET /health
 func (d *daemonServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -274,4 +515,93 @@ func (d *daemonServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
+}
+
+// commandMetric is a per-command metric reported by /metrics
+type commandMetric struct {
+	name  string
+	kind  string
+	help  string
+	value func(runner.CommandResult) float64
+}
+
+// latestMetrics describe the latest run of each command
+var latestMetrics = []commandMetric{
+	{"kwatch_command_passed", "gauge", "Whether the latest run passed (1) or not (0).", func(r runner.CommandResult) float64 {
+		if r.Passed {
+			return 1
+		}
+		return 0
+	}},
+	{"kwatch_command_duration_seconds", "gauge", "Wall-clock time of the latest run.", func(r runner.CommandResult) float64 {
+		return r.Duration.Seconds()
+	}},
+	{"kwatch_command_user_cpu_seconds", "gauge", "User CPU time of the latest run.", func(r runner.CommandResult) float64 {
+		return r.UserCPU.Seconds()
+	}},
+	{"kwatch_command_system_cpu_seconds", "gauge", "System CPU time of the latest run.", func(r runner.CommandResult) float64 {
+		return r.SystemCPU.Seconds()
+	}},
+	{"kwatch_command_peak_rss_bytes", "gauge", "Peak resident memory of the latest run.", func(r runner.CommandResult) float64 {
+		return float64(r.PeakRSS)
+	}},
+	{"kwatch_command_issues", "gauge", "Issues reported by the latest run.", func(r runner.CommandResult) float64 {
+		return float64(r.IssueCount)
+	}},
+}
+
+// totalMetric is a per-command counter reported by /metrics
+type totalMetric struct {
+	name  string
+	help  string
+	value func(runner.CommandTotals) float64
+}
+
+// totalMetrics add up the runs recorded since the daemon started
+var totalMetrics = []totalMetric{
+	{"kwatch_command_runs_total", "Runs executed since the daemon started, not counting cache hits.", func(t runner.CommandTotals) float64 {
+		return float64(t.Runs)
+	}},
+	{"kwatch_command_cpu_seconds_total", "CPU time of the runs executed since the daemon started.", func(t runner.CommandTotals) float64 {
+		return t.CPUTime.Seconds()
+	}},
+}
+
+// handleMetrics handles GET /metrics, reporting the resource usage and
+// outcome of each command in the Prometheus text format
+func (d *daemonServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	latest := d.runner.GetLatestResults()
+	totals := d.runner.Totals()
+
+	var cmdTypes []runner.CommandType
+	for cmdType := range latest {
+		cmdTypes = append(cmdTypes, cmdType)
+	}
+	for cmdType := range totals {
+		if _, exists := latest[cmdType]; !exists {
+			cmdTypes = append(cmdTypes, cmdType)
+		}
+	}
+	runner.SortCommandTypes(cmdTypes)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	for _, metric := range latestMetrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		for _, cmdType := range cmdTypes {
+			if result, exists := latest[cmdType]; exists {
+				fmt.Fprintf(w, "%s{command=%q} %g\n", metric.name, string(cmdType), metric.value(result))
+			}
+		}
+	}
+	for _, metric := range totalMetrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", metric.name, metric.help, metric.name)
+		for _, cmdType := range cmdTypes {
+			fmt.Fprintf(w, "%s{command=%q} %g\n", metric.name, string(cmdType), metric.value(totals[cmdType]))
+		}
+	}
+}
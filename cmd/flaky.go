@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"kwatch/runner"
+)
+
+var (
+	flakyLimit    int
+	flakyFormat   string
+	flakyMinScore float64
+)
+
+// flakyResponse represents the JSON response for the flaky command
+type flakyResponse struct {
+	Directory string             `json:"directory"`
+	Count     int                `json:"count"`
+	Tests     []runner.FlakyTest `json:"tests"`
+}
+
+var flakyCmd = &cobra.Command{
+	Use:   "flaky [directory]",
+	Short: "Show tests that pass and fail on unchanged code",
+	Long: `Show tests that changed outcome between runs on the same inputs.
+
+Test runs record the git tree of the working tree they ran on. A test that
+both passed and failed on one tree is flaky; its score is the share of its
+repeated runs on a tree whose outcome differed from the previous run, from
+0 (stable) to 1. Tests are read from the history in .kwatch/history/, so
+only commands that report individual tests (go test, cargo test, or JUnit
+reports) are analyzed.
+
+Examples:
+  kwatch flaky                             # Show flaky tests, most flaky first
+  kwatch flaky --min-score 0.2             # Show only tests scoring at least 0.2
+  kwatch flaky --format json               # Show in JSON format`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := getWorkingDirectory(args)
+
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		store, err := runner.OpenHistoryStoreReadOnly(runner.HistoryDir(absDir))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening history: %v\n", err)
+			os.Exit(1)
+		}
+
+		history, err := store.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+			os.Exit(1)
+		}
+
+		var tests []runner.FlakyTest
+		for _, test := range runner.AnalyzeFlakiness(history) {
+			if test.Score >= flakyMinScore {
+				tests = append(tests, test)
+			}
+		}
+		if flakyLimit > 0 && len(tests) > flakyLimit {
+			tests = tests[:flakyLimit]
+		}
+
+		switch flakyFormat {
+		case "json":
+			outputFlakyJSON(absDir, tests)
+		default:
+			outputFlakyDefault(tests)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(flakyCmd)
+	flakyCmd.Flags().IntVarP(&flakyLimit, "limit", "l", 0, "Limit number of tests shown (0 for all)")
+	flakyCmd.Flags().StringVarP(&flakyFormat, "format", "f", "default", "Output format (default, json)")
+	flakyCmd.Flags().Float64Var(&flakyMinScore, "min-score", 0, "Only show tests with at least this flakiness score")
+}
+
+// outputFlakyJSON outputs flaky tests in JSON format
+func outputFlakyJSON(directory string, tests []runner.FlakyTest) {
+	if tests == nil {
+		tests = []runner.FlakyTest{}
+	}
+	response := flakyResponse{
+		Directory: directory,
+		Count:     len(tests),
+		Tests:     tests,
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(string(jsonBytes))
+}
+
+// outputFlakyDefault outputs flaky tests as a table
+func outputFlakyDefault(tests []runner.FlakyTest) {
+	if len(tests) == 0 {
+		fmt.Println("No flaky tests found.")
+		return
+	}
+
+	fmt.Printf("Flaky tests (%d):\n\n", len(tests))
+	fmt.Printf("%-6s %-6s %-10s %-20s %-12s %s\n", "SCORE", "FLIPS", "FAILED", "LAST FLIP", "COMMAND", "TEST")
+	fmt.Println(strings.Repeat("-", 80))
+
+	for _, test := range tests {
+		fmt.Printf("%-6.2f %-6d %-10s %-20s %-12s %s\n",
+			test.Score,
+			test.Flips,
+			fmt.Sprintf("%d/%d", test.Failures, test.Runs),
+			test.LastFlip.Format("2006-01-02 15:04:05"),
+			test.Command,
+			test.Name)
+	}
+}
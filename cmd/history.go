@@ -1,14 +1,12 @@
 package cmd
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
-	"time"
 
 	"github.com/spf13/cobra"
 	"kwatch/config"
@@ -19,6 +17,10 @@ var (
 	historyLimit  int
 	historyFormat string
 	historyFilter string
+	historyRun    string
+
+	historyRunsLimit  int
+	historyRunsFormat string
 )
 
 // historyResponse represents the JSON response for history command
@@ -34,12 +36,15 @@ var historyCmd = &cobra.Command{
 	Long: `Show the history of command executions for the project.
 
 The history includes all previous runs with timestamps, durations, and results.
+It is stored in .kwatch/history/ and shared by the TUI, daemon, MCP server
+and one-shot commands, so it survives restarts.
 You can filter by command type and limit the number of results shown.
 
 Examples:
   kwatch history                           # Show all history
   kwatch history --limit 10                # Show last 10 entries
   kwatch history --filter tsc              # Show only TypeScript check history
+  kwatch history --run <run-id>            # Show the results of one run
   kwatch --dir /path/to/project history    # Show history for specific directory (flag)
   kwatch . history                         # Show history for current directory
   kwatch history --format table           # Show in table format
@@ -60,34 +65,26 @@ Examples:
 			os.Exit(1)
 		}
 
-		// Load kwatch configuration
-		kwatchConfig, err := config.Load(absDir)
+		// Read the history recorded by every kwatch frontend
+		store, err := runner.OpenHistoryStoreReadOnly(runner.HistoryDir(absDir))
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading kwatch config: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error opening history: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Create runner configuration
-		runnerConfig := runner.RunnerConfig{
-			DefaultTimeout: 30 * time.Second,
-			MaxParallel:    kwatchConfig.MaxParallel,
-			WorkingDir:     absDir,
+		history, err := store.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+			os.Exit(1)
 		}
 
-		r := runner.NewRunner(runnerConfig, kwatchConfig)
-		
-		// For this demo, we'll run once to populate history
-		// In a real implementation, history would be persistent
-		ctx := context.Background()
-		r.RunAll(ctx)
-
-		// Get history
-		history := r.GetHistory()
-
 		// Filter history if requested
 		if historyFilter != "" {
 			history = filterHistory(history, historyFilter)
 		}
+		if historyRun != "" {
+			history = filterHistoryByRun(history, historyRun)
+		}
 
 		// Sort by timestamp (newest first)
 		sort.Slice(history, func(i, j int) bool {
@@ -111,36 +108,183 @@ Examples:
 	},
 }
 
+var historyPruneCmd = &cobra.Command{
+	Use:   "prune [directory]",
+	Short: "Remove history entries outside the retention policy",
+	Long: `Apply the history retention policy from .kwatch/kwatch.yaml now.
+
+Entries beyond history.maxEntries, older than history.maxAge, and passing
+entries older than history.failuresOnlyAfter are removed. Stored outputs no
+longer referenced by any entry are deleted.
+
+Examples:
+  kwatch history prune                     # Prune history for current directory
+  kwatch history prune /path/to/project    # Prune history for specific directory`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := getWorkingDirectory(args)
+
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Load kwatch configuration
+		kwatchConfig, err := config.Load(absDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading kwatch config: %v\n", err)
+			os.Exit(1)
+		}
+
+		store, err := runner.OpenHistoryStore(runner.HistoryDir(absDir))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening history: %v\n", err)
+			os.Exit(1)
+		}
+
+		stats, err := store.Prune(runner.RetentionFromConfig(kwatchConfig))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error pruning history: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Removed %d entries, kept %d, deleted %d unused outputs\n",
+			stats.Removed, stats.Kept, stats.OutputsRemoved)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyPruneCmd)
+	historyCmd.AddCommand(historyRunsCmd)
 	historyCmd.Flags().IntVarP(&historyLimit, "limit", "l", 0, "Limit number of history entries (0 for all)")
 	historyCmd.Flags().StringVarP(&historyFormat, "format", "f", "default", "Output format (default, json, table)")
-	historyCmd.Flags().StringVar(&historyFilter, "filter", "", "Filter by command type (tsc, lint, test)")
+	historyCmd.Flags().StringVar(&historyFilter, "filter", "", "Filter by command type (tsc, lint, test, go_test, ...)")
+	historyCmd.Flags().StringVar(&historyRun, "run", "", "Show only the results of the run with this ID")
+	historyRunsCmd.Flags().IntVarP(&historyRunsLimit, "limit", "l", 20, "Limit number of runs (0 for all)")
+	historyRunsCmd.Flags().StringVarP(&historyRunsFormat, "format", "f", "default", "Output format (default, json)")
+}
+
+var historyRunsCmd = &cobra.Command{
+	Use:   "runs [directory]",
+	Short: "List recent runs",
+	Long: `List recent runs, newest first.
+
+A run groups the commands started together by one trigger: a file change,
+a manual run, an API or MCP request, or a scheduled scan. Each run records
+the git commit it started on and whether the working tree was dirty. Use
+kwatch history --run <id> to show a run's results.
+
+Examples:
+  kwatch history runs                      # List the last 20 runs
+  kwatch history runs --limit 0            # List every run
+  kwatch history runs --format json        # Show runs with their results in JSON`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := getWorkingDirectory(args)
+
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		store, err := runner.OpenHistoryStoreReadOnly(runner.HistoryDir(absDir))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening history: %v\n", err)
+			os.Exit(1)
+		}
+
+		runs := runner.NewResultHistory(store).Runs()
+		if historyRunsLimit > 0 && len(runs) > historyRunsLimit {
+			runs = runs[:historyRunsLimit]
+		}
+
+		switch historyRunsFormat {
+		case "json":
+			if runs == nil {
+				runs = []runner.RunResult{}
+			}
+			jsonBytes, err := json.MarshalIndent(runs, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(jsonBytes))
+		default:
+			outputRunsTable(runs)
+		}
+	},
+}
+
+// filterHistoryByRun keeps the history entries that belong to a run
+func filterHistoryByRun(history []runner.CommandResult, runID string) []runner.CommandResult {
+	var filtered []runner.CommandResult
+	for _, entry := range history {
+		if entry.RunRef == runID {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// outputRunsTable outputs runs as a table
+func outputRunsTable(runs []runner.RunResult) {
+	if len(runs) == 0 {
+		fmt.Println("No runs recorded.")
+		return
+	}
+
+	fmt.Printf("%-26s %-12s %-20s %-10s %-10s %-10s %s\n", "RUN", "TRIGGER", "STARTED", "DURATION", "COMMIT", "PASSED", "CHANGED")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for _, run := range runs {
+		commit := "-"
+		if run.GitSHA != "" {
+			commit = run.GitSHA[:min(7, len(run.GitSHA))]
+			if run.Dirty {
+				commit += "*"
+			}
+		}
+
+		passed := 0
+		for _, result := range run.Commands {
+			if result.State().Succeeded() {
+				passed++
+			}
+		}
+
+		changed := ""
+		if len(run.ChangedFiles) > 0 {
+			changed = truncateString(strings.Join(run.ChangedFiles, ", "), 40)
+		}
+
+		fmt.Printf("%-26s %-12s %-20s %-10s %-10s %-10s %s\n",
+			run.ID,
+			run.Trigger,
+			run.Timestamp.Format("2006-01-02 15:04:05"),
+			formatDuration(run.Duration),
+			commit,
+			fmt.Sprintf("%d/%d", passed, len(run.Commands)),
+			changed)
+	}
 }
 
 // filterHistory filters history entries by command type
 func filterHistory(history []runner.CommandResult, filter string) []runner.CommandResult {
 	var filtered []runner.CommandResult
 	
+	checker, known := runner.FindChecker(filter)
 	for _, entry := range history {
-		// Match command type
-		switch filter {
-		case "tsc", "typescript":
-			if strings.Contains(entry.Command, "tsc") {
-				filtered = append(filtered, entry)
-			}
-		case "lint", "eslint":
-			if strings.Contains(entry.Command, "lint") || strings.Contains(entry.Command, "eslint") {
-				filtered = append(filtered, entry)
-			}
-		case "test":
-			if strings.Contains(entry.Command, "test") {
-				filtered = append(filtered, entry)
-			}
-		default:
-			if strings.Contains(strings.ToLower(entry.Command), strings.ToLower(filter)) {
+		// Match the checker's command type, or custom commands by name
+		if known {
+			if entry.CommandType() == checker.Type() {
 				filtered = append(filtered, entry)
 			}
+		} else if entry.CommandType() == runner.CommandType(filter) ||
+			strings.Contains(strings.ToLower(entry.Command), strings.ToLower(filter)) {
+			filtered = append(filtered, entry)
 		}
 	}
 	
@@ -172,28 +316,39 @@ func outputHistoryTable(history []runner.CommandResult) {
 	}
 
 	// Table header
-	fmt.Printf("%-20s %-10s %-8s %-8s %-10s %s\n", "TIMESTAMP", "COMMAND", "PASSED", "ISSUES", "DURATION", "ERROR")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%-20s %-10s %-8s %-8s %-10s %-10s %-10s %s\n", "TIMESTAMP", "COMMAND", "PASSED", "ISSUES", "DURATION", "CPU", "PEAK RSS", "ERROR")
+	fmt.Println(strings.Repeat("-", 100))
 
 	// Table rows
 	for _, entry := range history {
 		timestamp := entry.Timestamp.Format("2006-01-02 15:04:05")
-		command := getCommandTypeLabel(entry.Command)
-		passed := "✓"
-		if !entry.Passed {
-			passed = "✗"
-		}
+		command := getCommandTypeLabel(entry)
+		passed := runner.StatusSymbol(entry.State())
 		duration := formatDuration(entry.Duration)
+		cpu, rss := formatUsage(entry)
 		errorMsg := ""
 		if entry.Error != "" {
 			errorMsg = truncateString(entry.Error, 30)
 		}
 
-		fmt.Printf("%-20s %-10s %-8s %-8d %-10s %s\n", 
-			timestamp, command, passed, entry.IssueCount, duration, errorMsg)
+		fmt.Printf("%-20s %-10s %-8s %-8d %-10s %-10s %-10s %s\n", 
+			timestamp, command, passed, entry.IssueCount, duration, cpu, rss, errorMsg)
 	}
 }
 
+// formatUsage formats the CPU time and peak RSS of a result, or dashes
+// for results that did not run a process
+func formatUsage(result runner.CommandResult) (cpu, rss string) {
+	cpu, rss = "-", "-"
+	if result.CPUTime() > 0 {
+		cpu = formatDuration(result.CPUTime())
+	}
+	if result.PeakRSS > 0 {
+		rss = runner.FormatBytes(result.PeakRSS)
+	}
+	return cpu, rss
+}
+
 // outputHistoryDefault outputs history in default format
 func outputHistoryDefault(history []runner.CommandResult) {
 	if len(history) == 0 {
@@ -204,13 +359,10 @@ func outputHistoryDefault(history []runner.CommandResult) {
 	fmt.Printf("Command History (%d entries):\n\n", len(history))
 
 	for i, entry := range history {
-		status := "PASSED"
-		if !entry.Passed {
-			status = "FAILED"
-		}
+		status := strings.ToUpper(string(entry.State()))
 
 		fmt.Printf("%d. %s - %s (%s)\n", i+1, 
-			getCommandTypeLabel(entry.Command), 
+			getCommandTypeLabel(entry), 
 			status, 
 			entry.Timestamp.Format("2006-01-02 15:04:05"))
 		
@@ -228,20 +380,9 @@ func outputHistoryDefault(history []runner.CommandResult) {
 	}
 }
 
-// getCommandTypeLabel returns a human-readable label for a command
-func getCommandTypeLabel(command string) string {
-	switch {
-	case strings.Contains(command, "tsc"):
-		return "TypeScript"
-	case strings.Contains(command, "lint") || strings.Contains(command, "eslint"):
-		return "Lint"
-	case strings.Contains(command, "test"):
-		return "Test"
-	case strings.Contains(command, "github"):
-		return "GitHub Actions"
-	default:
-		return command
-	}
+// getCommandTypeLabel returns a human-readable label for the command of a result
+func getCommandTypeLabel(result runner.CommandResult) string {
+	return result.CommandType().Name()
 }
 
 // truncateString truncates a string to the specified length
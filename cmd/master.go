@@ -33,6 +33,7 @@ type WatchedDirectory struct {
 // DirectoryCommand represents a command result for a directory
 type DirectoryCommand struct {
 	Passed     bool          `json:"passed"`
+	Status     string        `json:"status"`
 	IssueCount int           `json:"issue_count"`
 	Duration   time.Duration `json:"duration"`
 	LastRun    time.Time     `json:"last_run"`
@@ -98,7 +99,7 @@ Examples:
 			runMasterWatch(dirs)
 		} else {
 			// Single run mode
-			runMasterSingle(dirs)
+			runMasterSingle(dirs, runner.TriggerManual)
 		}
 	},
 }
@@ -141,8 +142,8 @@ func discoverKWatchDirectories() ([]string, error) {
 }
 
 // runMasterSingle runs a single scan of all directories
-func runMasterSingle(dirs []string) {
-	status := scanDirectories(dirs)
+func runMasterSingle(dirs []string, trigger runner.Trigger) {
+	status := scanDirectories(dirs, trigger)
 	
 	switch masterFormat {
 	case "json":
@@ -166,20 +167,20 @@ func runMasterWatch(dirs []string) {
 	defer ticker.Stop()
 	
 	// Initial scan
-	runMasterSingle(dirs)
+	runMasterSingle(dirs, runner.TriggerManual)
 	
 	for {
 		select {
 		case <-ticker.C:
 			fmt.Print("\033[H\033[2J") // Clear screen
 			fmt.Printf("Master KWatch - Last updated: %s\n", time.Now().Format("15:04:05"))
-			runMasterSingle(dirs)
+			runMasterSingle(dirs, runner.TriggerSchedule)
 		}
 	}
 }
 
 // scanDirectories scans all directories and returns consolidated status
-func scanDirectories(dirs []string) MasterStatus {
+func scanDirectories(dirs []string, trigger runner.Trigger) MasterStatus {
 	status := MasterStatus{
 		Timestamp:   time.Now(),
 		Directories: make(map[string]WatchedDirectory),
@@ -219,24 +220,15 @@ func scanDirectories(dirs []string) MasterStatus {
 		r := runner.NewRunner(runnerConfig, kwatchConfig)
 		
 		// Run all commands
-		results := r.RunAll(ctx)
+		results := r.RunAll(ctx, trigger).Commands
 		
 		// Convert results to directory commands
-		cmdNames := map[runner.CommandType]string{
-			runner.TypescriptCheck: "tsc",
-			runner.LintCheck:       "lint",
-			runner.TestRunner:      "test",
-			runner.GitHubActions:   "github",
-		}
-		
 		for cmdType, result := range results {
-			cmdName := cmdNames[cmdType]
-			if cmdName == "" {
-				cmdName = string(cmdType)
-			}
+			cmdName := cmdType.ShortName()
 			
 			watched.Commands[cmdName] = DirectoryCommand{
 				Passed:     result.Passed,
+				Status:     string(result.State()),
 				IssueCount: result.IssueCount,
 				Duration:   result.Duration,
 				LastRun:    result.Timestamp,
@@ -313,16 +305,16 @@ func outputMasterCompact(status MasterStatus) {
 		}
 		
 		var parts []string
-		cmdOrder := []string{"tsc", "lint", "test", "github"}
+		var cmdNames []string
+		for cmdName := range dir.Commands {
+			cmdNames = append(cmdNames, cmdName)
+		}
+		sortCommandNames(cmdNames)
 		
-		for _, cmdName := range cmdOrder {
-			if cmd, exists := dir.Commands[cmdName]; exists {
-				symbol := "✓"
-				if !cmd.Passed {
-					symbol = "✗"
-				}
-				parts = append(parts, fmt.Sprintf("%s:%s", strings.ToUpper(cmdName), symbol))
-			}
+		for _, cmdName := range cmdNames {
+			cmd := dir.Commands[cmdName]
+			symbol := runner.StatusSymbol(runner.ResultStatus(cmd.Status))
+			parts = append(parts, fmt.Sprintf("%s:%s", commandTypeForName(cmdName).Symbol(), symbol))
 		}
 		
 		fmt.Printf("%s: %s\n", name, strings.Join(parts, " "))
@@ -346,28 +338,12 @@ func outputMasterMatrix(status MasterStatus) {
 		}
 	}
 	
-	// Command order preference
-	cmdOrder := []string{"tsc", "lint", "test", "github"}
+	// Registered checkers in registration order, then custom commands
 	var commands []string
-	for _, cmd := range cmdOrder {
-		if allCommands[cmd] {
-			commands = append(commands, cmd)
-		}
-	}
-	
-	// Add any other commands not in the preferred order
 	for cmd := range allCommands {
-		found := false
-		for _, orderedCmd := range commands {
-			if cmd == orderedCmd {
-				found = true
-				break
-			}
-		}
-		if !found {
-			commands = append(commands, cmd)
-		}
+		commands = append(commands, cmd)
 	}
+	sortCommandNames(commands)
 	
 	// Print header
 	fmt.Printf("Master KWatch Status - %s\n", status.Timestamp.Format("2006-01-02 15:04:05"))
@@ -413,7 +389,15 @@ func outputMasterMatrix(status MasterStatus) {
 			for _, cmdName := range commands {
 				if cmd, exists := dir.Commands[cmdName]; exists {
 					var status string
-					if cmd.Passed {
+					if cmd.Status == string(runner.StatusSkipped) {
+						status = "SKIP"
+					} else if cmd.Status == string(runner.StatusTimedOut) {
+						status = "TIMEOUT"
+					} else if cmd.Status == string(runner.StatusUnstable) {
+						status = "UNSTABLE"
+					} else if cmd.Status == string(runner.StatusResourceExceeded) {
+						status = "LIMIT"
+					} else if cmd.Passed {
 						if cmd.IssueCount == 0 {
 							status = "✓"
 						} else {
@@ -436,6 +420,20 @@ func outputMasterMatrix(status MasterStatus) {
 	}
 	
 	fmt.Println()
-	fmt.Printf("Legend: ✓ = Passed, ✗ = Failed, ERR = Error, (-) = Not applicable\n")
+	fmt.Printf("Legend: ✓ = Passed, ✗ = Failed, ERR = Error, SKIP = Dependency failed, TIMEOUT = Timed out, (-) = Not applicable\n")
 	fmt.Printf("Numbers in parentheses show issue count\n")
-}
\ No newline at end of file
+}
+
+// sortCommandNames orders short command names like their command types
+func sortCommandNames(names []string) {
+	types := make([]runner.CommandType, len(names))
+	byType := make(map[runner.CommandType]string, len(names))
+	for i, name := range names {
+		types[i] = commandTypeForName(name)
+		byType[types[i]] = name
+	}
+	runner.SortCommandTypes(types)
+	for i, cmdType := range types {
+		names[i] = byType[cmdType]
+	}
+}
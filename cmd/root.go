@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"kwatch/runner"
 	"kwatch/tui"
 )
 
@@ -59,4 +60,13 @@ func startTUI(dir string) {
 		fmt.Fprintf(os.Stderr, "Error starting TUI: %v\n", err)
 		os.Exit(1)
 	}
-}
\ No newline at end of file
+}
+
+// commandTypeForName returns the command type a name refers to: a
+// checker's type, short name or alias, or a custom command's name
+func commandTypeForName(name string) runner.CommandType {
+	if checker, exists := runner.FindChecker(name); exists {
+		return checker.Type()
+	}
+	return runner.CommandType(name)
+}
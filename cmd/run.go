@@ -24,6 +24,7 @@ var (
 type runResponse struct {
 	Directory string                             `json:"directory"`
 	Timestamp string                             `json:"timestamp"`
+	RunID     string                             `json:"run_id"`
 	Summary   runSummary                         `json:"summary"`
 	Results   map[string]runCommandResult        `json:"results"`
 }
@@ -33,17 +34,23 @@ type runSummary struct {
 	Total    int `json:"total"`
 	Passed   int `json:"passed"`
 	Failed   int `json:"failed"`
+	Unstable int `json:"unstable,omitempty"`
+	Skipped  int `json:"skipped"`
 	Duration string `json:"duration"`
 }
 
 // runCommandResult represents a command result in the run response
 type runCommandResult struct {
-	Command    string `json:"command"`
-	Passed     bool   `json:"passed"`
-	IssueCount int    `json:"issue_count"`
-	Duration   string `json:"duration"`
-	Output     string `json:"output,omitempty"`
-	Error      string `json:"error,omitempty"`
+	Command    string           `json:"command"`
+	Passed     bool             `json:"passed"`
+	Status     string           `json:"status"`
+	IssueCount int              `json:"issue_count"`
+	Duration   string           `json:"duration"`
+	Cached     bool             `json:"cached,omitempty"`
+	ExitCode   int              `json:"exit_code,omitempty"`
+	Attempts   []runner.Attempt `json:"attempts,omitempty"`
+	Output     string           `json:"output,omitempty"`
+	Error      string           `json:"error,omitempty"`
 }
 
 var runCmd = &cobra.Command{
@@ -97,14 +104,14 @@ Examples:
 		ctx := context.Background()
 		start := time.Now()
 
-		var results map[runner.CommandType]runner.CommandResult
+		var run runner.RunResult
 
 		if runCommand != "" {
 			// Run specific command
-			results = runSpecificCommand(ctx, r, runCommand)
+			run = runSpecificCommand(ctx, r, runCommand)
 		} else {
 			// Run all commands
-			results = r.RunAll(ctx)
+			run = r.RunAll(ctx, runner.TriggerManual)
 		}
 
 		totalDuration := time.Since(start)
@@ -112,81 +119,42 @@ Examples:
 		// Output results based on format
 		switch runFormat {
 		case "json":
-			outputRunJSON(absDir, results, totalDuration)
+			outputRunJSON(absDir, run, totalDuration)
 		case "compact":
-			outputRunCompact(results)
+			outputRunCompact(run.Commands)
 		default:
-			outputRunDefault(results, totalDuration)
+			outputRunDefault(run, totalDuration)
 		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(runCmd)
-	runCmd.Flags().StringVarP(&runCommand, "command", "c", "", "Run specific command (tsc, lint, test, github)")
+	runCmd.Flags().StringVarP(&runCommand, "command", "c", "", "Run specific command by name (tsc, lint, test, go_test, github, ...)")
 	runCmd.Flags().BoolVarP(&runVerbose, "verbose", "v", false, "Show verbose output including command output")
 	runCmd.Flags().StringVarP(&runFormat, "format", "f", "default", "Output format (default, json, compact)")
 }
 
-// runSpecificCommand runs a specific command type
-func runSpecificCommand(ctx context.Context, r *runner.Runner, cmdType string) map[runner.CommandType]runner.CommandResult {
-	results := make(map[runner.CommandType]runner.CommandResult)
-
-	// Map command string to command type
-	var targetType runner.CommandType
-	var cmd runner.Command
-
-	switch strings.ToLower(cmdType) {
-	case "tsc", "typescript":
-		targetType = runner.TypescriptCheck
-		cmd = runner.Command{
-			Type:    runner.TypescriptCheck,
-			Command: "npx",
-			Args:    []string{"tsc", "--noEmit"},
-			Timeout: 30 * time.Second,
-		}
-	case "lint", "eslint":
-		targetType = runner.LintCheck
-		cmd = runner.Command{
-			Type:    runner.LintCheck,
-			Command: "npx",
-			Args:    []string{"eslint", ".", "--ext", ".ts,.tsx,.js,.jsx"},
-			Timeout: 30 * time.Second,
-		}
-	case "test":
-		targetType = runner.TestRunner
-		cmd = runner.Command{
-			Type:    runner.TestRunner,
-			Command: "npm",
-			Args:    []string{"test"},
-			Timeout: 60 * time.Second,
-		}
-	case "github", "github_actions", "gh":
-		targetType = runner.GitHubActions
-		cmd = runner.Command{
-			Type:    runner.GitHubActions,
-			Command: "github_actions",
-			Args:    []string{},
-			Timeout: 30 * time.Second,
-		}
-	default:
+// runSpecificCommand runs a specific command type as a run of its own
+func runSpecificCommand(ctx context.Context, r *runner.Runner, cmdType string) runner.RunResult {
+	// Look up the checker or custom command the name refers to
+	cmd, exists := r.ResolveCommand(cmdType)
+	if !exists {
 		fmt.Fprintf(os.Stderr, "Unknown command type: %s\n", cmdType)
-		fmt.Fprintf(os.Stderr, "Available commands: tsc, lint, test, github\n")
+		fmt.Fprintf(os.Stderr, "Available commands: %s\n", strings.Join(r.CommandNames(), ", "))
 		os.Exit(1)
 	}
 
-	// Run the specific command
-	result := r.RunCommand(ctx, cmd)
-	results[targetType] = result
-
-	return results
+	return r.RunOne(ctx, runner.TriggerManual, cmd)
 }
 
 // outputRunJSON outputs run results in JSON format
-func outputRunJSON(directory string, results map[runner.CommandType]runner.CommandResult, totalDuration time.Duration) {
+func outputRunJSON(directory string, run runner.RunResult, totalDuration time.Duration) {
+	results := run.Commands
 	response := runResponse{
 		Directory: directory,
 		Timestamp: time.Now().Format(time.RFC3339),
+		RunID:     run.ID,
 		Results:   make(map[string]runCommandResult),
 	}
 
@@ -194,25 +162,21 @@ func outputRunJSON(directory string, results map[runner.CommandType]runner.Comma
 	total := len(results)
 	passed := 0
 	failed := 0
-
-	cmdNames := map[runner.CommandType]string{
-		runner.TypescriptCheck: "tsc",
-		runner.LintCheck:       "lint",
-		runner.TestRunner:      "test",
-		runner.GitHubActions:   "github",
-	}
+	unstable := 0
+	skipped := 0
 
 	for cmdType, result := range results {
-		cmdName := cmdNames[cmdType]
-		if cmdName == "" {
-			cmdName = string(cmdType)
-		}
+		cmdName := cmdType.ShortName()
 
 		runResult := runCommandResult{
 			Command:    result.Command,
 			Passed:     result.Passed,
+			Status:     string(result.State()),
 			IssueCount: result.IssueCount,
 			Duration:   formatDuration(result.Duration),
+			Cached:     result.Cached,
+			ExitCode:   result.ExitCode,
+			Attempts:   result.Attempts,
 		}
 
 		if runVerbose {
@@ -222,9 +186,14 @@ func outputRunJSON(directory string, results map[runner.CommandType]runner.Comma
 
 		response.Results[cmdName] = runResult
 
-		if result.Passed {
+		switch result.State() {
+		case runner.StatusPassed:
 			passed++
-		} else {
+		case runner.StatusUnstable:
+			unstable++
+		case runner.StatusSkipped:
+			skipped++
+		default:
 			failed++
 		}
 	}
@@ -233,6 +202,8 @@ func outputRunJSON(directory string, results map[runner.CommandType]runner.Comma
 		Total:    total,
 		Passed:   passed,
 		Failed:   failed,
+		Unstable: unstable,
+		Skipped:  skipped,
 		Duration: formatDuration(totalDuration),
 	}
 
@@ -252,30 +223,45 @@ func outputRunCompact(results map[runner.CommandType]runner.CommandResult) {
 }
 
 // outputRunDefault outputs run results in default format
-func outputRunDefault(results map[runner.CommandType]runner.CommandResult, totalDuration time.Duration) {
-	fmt.Printf("Running commands...\n\n")
+func outputRunDefault(run runner.RunResult, totalDuration time.Duration) {
+	results := run.Commands
+	fmt.Printf("Running commands (run %s)...\n\n", run.ID)
 
 	total := len(results)
 	passed := 0
 	failed := 0
+	unstable := 0
+	skipped := 0
 
 	// Display results for each command
 	for _, result := range results {
-		cmdName := getCommandTypeLabel(result.Command)
+		cmdName := getCommandTypeLabel(result)
 		
-		status := "✓ PASSED"
-		if !result.Passed {
-			status = "✗ FAILED"
-			failed++
-		} else {
+		state := result.State()
+		status := fmt.Sprintf("%s %s", runner.StatusSymbol(state), strings.ToUpper(string(state)))
+		switch state {
+		case runner.StatusPassed:
 			passed++
+		case runner.StatusUnstable:
+			unstable++
+		case runner.StatusSkipped:
+			skipped++
+		default:
+			failed++
 		}
 
 		fmt.Printf("%s: %s", cmdName, status)
+		if attempts := result.AttemptSummary(); attempts != "" {
+			fmt.Printf(" %s", attempts)
+		}
 		if result.IssueCount > 0 {
 			fmt.Printf(" (%d issues)", result.IssueCount)
 		}
-		fmt.Printf(" in %s\n", formatDuration(result.Duration))
+		if result.Cached {
+			fmt.Printf(" (cached)\n")
+		} else {
+			fmt.Printf(" in %s\n", formatDuration(result.Duration))
+		}
 
 		if runVerbose && result.Output != "" {
 			fmt.Printf("  Output: %s\n", truncateString(result.Output, 200))
@@ -291,6 +277,12 @@ func outputRunDefault(results map[runner.CommandType]runner.CommandResult, total
 	if failed > 0 {
 		fmt.Printf(", %d failed", failed)
 	}
+	if unstable > 0 {
+		fmt.Printf(", %d unstable", unstable)
+	}
+	if skipped > 0 {
+		fmt.Printf(", %d skipped", skipped)
+	}
 	fmt.Printf(" (completed in %s)\n", formatDuration(totalDuration))
 
 	// Exit with error code if any command failed
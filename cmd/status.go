@@ -21,14 +21,25 @@ var (
 type statusResponse struct {
 	Directory string                             `json:"directory"`
 	Timestamp string                             `json:"timestamp"`
+	RunID     string                             `json:"run_id"`
 	Commands  map[string]statusCommandResult    `json:"commands"`
 }
 
 // statusCommandResult represents a command result in the status response
 type statusCommandResult struct {
-	Passed     bool   `json:"passed"`
-	IssueCount int    `json:"issue_count"`
-	Duration   string `json:"duration"`
+	Passed        bool                `json:"passed"`
+	Status        string              `json:"status"`
+	IssueCount    int                 `json:"issue_count"`
+	FileCount     int                 `json:"file_count,omitempty"`
+	ErrorCount    int                 `json:"error_count,omitempty"`
+	WarningCount  int                 `json:"warning_count,omitempty"`
+	FixableCount  int                 `json:"fixable_count,omitempty"`
+	Duration      string              `json:"duration"`
+	Cached        bool                `json:"cached,omitempty"`
+	Coverage      *runner.Coverage    `json:"coverage,omitempty"`
+	Attempts      []runner.Attempt    `json:"attempts,omitempty"`
+	LimitExceeded string              `json:"limit_exceeded,omitempty"`
+	Diagnostics   []runner.Diagnostic `json:"diagnostics,omitempty"`
 }
 
 var statusCmd = &cobra.Command{
@@ -79,7 +90,8 @@ Examples:
 		ctx := context.Background()
 
 		// Run all commands
-		results := r.RunAll(ctx)
+		run := r.RunAll(ctx, runner.TriggerManual)
+		results := run.Commands
 
 		if compactFlag {
 			// Output compact status
@@ -90,27 +102,28 @@ Examples:
 			response := statusResponse{
 				Directory: absDir,
 				Timestamp: time.Now().Format(time.RFC3339),
+				RunID:     run.ID,
 				Commands:  make(map[string]statusCommandResult),
 			}
 
 			// Convert results to response format
-			cmdNames := map[runner.CommandType]string{
-				runner.TypescriptCheck: "tsc",
-				runner.LintCheck:       "lint",
-				runner.TestRunner:      "test",
-				runner.GitHubActions:   "github",
-			}
-
 			for cmdType, result := range results {
-				cmdName := cmdNames[cmdType]
-				if cmdName == "" {
-					cmdName = string(cmdType)
-				}
+				cmdName := cmdType.ShortName()
 
 				response.Commands[cmdName] = statusCommandResult{
-					Passed:     result.Passed,
-					IssueCount: result.IssueCount,
-					Duration:   formatDuration(result.Duration),
+					Passed:        result.Passed,
+					Status:        string(result.State()),
+					IssueCount:    result.IssueCount,
+					FileCount:     result.FileCount,
+					ErrorCount:    result.ErrorCount,
+					WarningCount:  result.WarningCount,
+					FixableCount:  result.FixableCount,
+					Duration:      formatDuration(result.Duration),
+					Cached:        result.Cached,
+					Coverage:      result.Coverage,
+					Attempts:      result.Attempts,
+					LimitExceeded: result.LimitExceeded,
+					Diagnostics:   result.Diagnostics,
 				}
 			}
 
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"kwatch/config"
+	"kwatch/runner"
+)
+
+var trendsFormat string
+
+// trendsResponse represents the JSON response for the trends command
+type trendsResponse struct {
+	Directory   string              `json:"directory"`
+	Trends      []runner.Trend      `json:"trends"`
+	Regressions []runner.Regression `json:"regressions"`
+}
+
+var trendsCmd = &cobra.Command{
+	Use:   "trends [directory]",
+	Short: "Show duration and issue trends and regressions",
+	Long: `Show how each command's duration and issue count developed.
+
+The median of a command's latest runs is compared to the median of the runs
+before them. A regression is reported when the duration rose by at least
+trends.durationIncrease percent (default 40) or the issue count by at least
+trends.issueIncrease (default 1). Cached, incremental and interrupted runs
+are left out. Runs are read from the history in .kwatch/history/.
+
+Examples:
+  kwatch trends                            # Show trends for current directory
+  kwatch trends /path/to/project           # Show trends for specific directory
+  kwatch trends --format json              # Show in JSON format`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := getWorkingDirectory(args)
+
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Load kwatch configuration
+		kwatchConfig, err := config.Load(absDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading kwatch config: %v\n", err)
+			os.Exit(1)
+		}
+
+		store, err := runner.OpenHistoryStoreReadOnly(runner.HistoryDir(absDir))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening history: %v\n", err)
+			os.Exit(1)
+		}
+
+		history, err := store.Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+			os.Exit(1)
+		}
+
+		trends := runner.AnalyzeTrends(history, runner.TrendPolicyFromConfig(kwatchConfig))
+
+		switch trendsFormat {
+		case "json":
+			outputTrendsJSON(absDir, trends)
+		default:
+			outputTrendsDefault(trends)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trendsCmd)
+	trendsCmd.Flags().StringVarP(&trendsFormat, "format", "f", "default", "Output format (default, json)")
+}
+
+// trendRegressions returns the regressions of all trends
+func trendRegressions(trends []runner.Trend) []runner.Regression {
+	regressions := []runner.Regression{}
+	for _, trend := range trends {
+		regressions = append(regressions, trend.Regressions...)
+	}
+	return regressions
+}
+
+// outputTrendsJSON outputs trends in JSON format
+func outputTrendsJSON(directory string, trends []runner.Trend) {
+	response := trendsResponse{
+		Directory:   directory,
+		Trends:      trends,
+		Regressions: trendRegressions(trends),
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting JSON: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(string(jsonBytes))
+}
+
+// outputTrendsDefault outputs trends as a table followed by regressions
+func outputTrendsDefault(trends []runner.Trend) {
+	if len(trends) == 0 {
+		fmt.Println("No runs recorded yet.")
+		return
+	}
+
+	fmt.Printf("%-14s %-5s %-10s %-10s %-8s %-12s %s\n", "COMMAND", "RUNS", "BASELINE", "RECENT", "CHANGE", "ISSUES", "DURATIONS")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, trend := range trends {
+		baseline, recent, change, issues := "-", "-", "-", "-"
+		if trend.BaselineDuration > 0 {
+			baseline = formatDuration(trend.BaselineDuration)
+			recent = formatDuration(trend.RecentDuration)
+			change = fmt.Sprintf("%+.0f%%", trend.DurationChange)
+			issues = fmt.Sprintf("%g → %g", trend.BaselineIssues, trend.RecentIssues)
+		}
+		fmt.Printf("%-14s %-5d %-10s %-10s %-8s %-12s %s\n",
+			trend.Command, trend.Runs, baseline, recent, change, issues, trend.DurationSparkline(24))
+	}
+
+	regressions := trendRegressions(trends)
+	if len(regressions) == 0 {
+		return
+	}
+	fmt.Printf("\nRegressions (%d):\n", len(regressions))
+	for _, regression := range regressions {
+		fmt.Printf("  ✗ %s\n", regression)
+	}
+}
@@ -3,7 +3,12 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
@@ -14,57 +19,202 @@ type Config struct {
 	DefaultTimeout string             `yaml:"defaultTimeout"`
 	MaxParallel    int               `yaml:"maxParallel"`
 	Commands       map[string]Command `yaml:"commands"`
+	History        HistoryConfig      `yaml:"history,omitempty"`
+	Flaky          FlakyConfig        `yaml:"flaky,omitempty"`
+	Trends         TrendsConfig       `yaml:"trends,omitempty"`
 }
 
+// TrendsConfig sets when a command's latest runs count as a regression
+// against the median of the runs before them
+type TrendsConfig struct {
+	// Window is the number of earlier runs forming the baseline (default 20)
+	Window int `yaml:"window,omitempty"`
+	// Recent is the number of latest runs compared to the baseline (default 5)
+	Recent int `yaml:"recent,omitempty"`
+	// DurationIncrease is the percentage by which the recent median
+	// duration must exceed the baseline (default 40)
+	DurationIncrease float64 `yaml:"durationIncrease,omitempty"`
+	// IssueIncrease is how many more issues than the baseline median the
+	// recent median must have (default 1)
+	IssueIncrease int `yaml:"issueIncrease,omitempty"`
+}
+
+// FlakyConfig controls how failures of known-flaky tests are reported.
+// A test is flaky when it both passed and failed on the same git tree.
+type FlakyConfig struct {
+	// Unstable marks runs whose only failures are known-flaky tests as
+	// unstable instead of failed
+	Unstable bool `yaml:"unstable,omitempty"`
+	// MinScore is the flakiness score, from 0 to 1, at which a test counts
+	// as known-flaky (default 0.1)
+	MinScore float64 `yaml:"minScore,omitempty"`
+}
+
+// HistoryConfig controls how long run history is kept in .kwatch/history/.
+// Ages accept Go durations ("12h") or days ("30d").
+type HistoryConfig struct {
+	// MaxEntries is the number of results kept (default 5000)
+	MaxEntries int `yaml:"maxEntries,omitempty"`
+	// MaxAge drops results older than this (default 30d)
+	MaxAge string `yaml:"maxAge,omitempty"`
+	// FailuresOnlyAfter drops passing results older than this, keeping failures
+	FailuresOnlyAfter string `yaml:"failuresOnlyAfter,omitempty"`
+}
+
+// Default history retention
+const (
+	DefaultHistoryMaxEntries = 5000
+	DefaultHistoryMaxAge     = 30 * 24 * time.Hour
+)
+
 // Command represents a single command configuration
 type Command struct {
 	Command string   `yaml:"command"`
 	Args    []string `yaml:"args"`
 	Timeout string   `yaml:"timeout"`
 	Enabled bool     `yaml:"enabled"`
+	// Weight is the number of maxParallel slots the command occupies (default 1)
+	Weight   int `yaml:"weight,omitempty"`
+	// Priority orders queued commands, higher values start first (default 0)
+	Priority int `yaml:"priority,omitempty"`
+	// DependsOn lists commands that must pass before this one runs
+	DependsOn []string `yaml:"dependsOn,omitempty"`
+	// Inputs are globs of the files the command reads ("src/**/*.ts").
+	// When set, a passing result is reused while the inputs, command and
+	// tool versions are unchanged.
+	Inputs []string `yaml:"inputs,omitempty"`
+	// IncrementalArgs are used instead of Args when the watcher runs the
+	// command on changed files; {{changedFiles}} expands to those files
+	IncrementalArgs []string `yaml:"incrementalArgs,omitempty"`
+	// IncrementalFiles are globs selecting which changed files are passed
+	IncrementalFiles []string `yaml:"incrementalFiles,omitempty"`
+	// Reports are globs of JUnit XML reports the command writes
+	// ("reports/junit*.xml"); reports written by a run supply its test
+	// counts and per-test results
+	Reports []string `yaml:"reports,omitempty"`
+	// Coverage reads the coverage report the command writes
+	Coverage CoverageConfig `yaml:"coverage,omitempty"`
+	// Success decides whether the command passed (default: exit code 0)
+	Success SuccessConfig `yaml:"success,omitempty"`
+	// Retries is how many times a failed run is retried (default 0)
+	Retries int `yaml:"retries,omitempty"`
+	// RetryOn limits retries to some failures (default: any failure)
+	RetryOn RetryOnConfig `yaml:"retryOn,omitempty"`
+	// RetryBackoff is the delay before the first retry, doubling for each
+	// further retry (default 1s)
+	RetryBackoff string `yaml:"retryBackoff,omitempty"`
+	// Limits caps the resources the command may use (Linux only)
+	Limits LimitsConfig `yaml:"limits,omitempty"`
+	// Parser declares how to read the command's output, replacing the
+	// built-in parser
+	Parser ParserConfig `yaml:"parser,omitempty"`
+}
+
+// SuccessConfig sets the pass/fail criteria of a command. The exit code
+// must be one of ExitCodes, the output must match every Require pattern
+// and no Forbid pattern. Patterns are Go regular expressions.
+type SuccessConfig struct {
+	// ExitCodes are the exit codes that count as success (default [0])
+	ExitCodes []int `yaml:"exitCodes,omitempty"`
+	// Require are patterns the output must match
+	Require []string `yaml:"require,omitempty"`
+	// Forbid are patterns the output must not match
+	Forbid []string `yaml:"forbid,omitempty"`
+}
+
+// RetryOnConfig selects the failures that are retried: those exiting with
+// one of the exit codes or whose output matches one of the regular
+// expressions
+type RetryOnConfig struct {
+	ExitCodes []int    `yaml:"exitCodes,omitempty"`
+	Output    []string `yaml:"output,omitempty"`
+}
+
+// LimitsConfig caps the resources of a command. Unset values are unlimited.
+type LimitsConfig struct {
+	// Memory is a size such as "512M" or "2GiB"
+	Memory string `yaml:"memory,omitempty"`
+	// CPUTime is the CPU time each process may use, such as "5m"
+	CPUTime   string `yaml:"cpuTime,omitempty"`
+	OpenFiles int    `yaml:"openFiles,omitempty"`
+	Processes int    `yaml:"processes,omitempty"`
+}
+
+// CoverageConfig points at a coverage report: an istanbul
+// coverage-summary.json, an lcov tracefile or a Go coverprofile
+type CoverageConfig struct {
+	// Report is the path of the report, relative to the project
+	Report string `yaml:"report,omitempty"`
+	// Format is istanbul, lcov or go (detected from the content if empty)
+	Format string `yaml:"format,omitempty"`
+	// MinCoverage fails the command when a percentage is below its minimum
+	MinCoverage CoverageThresholds `yaml:"minCoverage,omitempty"`
+}
+
+// CoverageThresholds are minimum coverage percentages (0 for no minimum)
+type CoverageThresholds struct {
+	Lines     float64 `yaml:"lines,omitempty"`
+	Branches  float64 `yaml:"branches,omitempty"`
+	Functions float64 `yaml:"functions,omitempty"`
+}
+
+// coverageFormats are the supported coverage report formats
+var coverageFormats = []string{"istanbul", "lcov", "go"}
+
+// ParserConfig declares regular expressions for reading a tool's output.
+// Diagnostic patterns match one diagnostic per line using the named groups
+// file, line, col, severity, rule and message; message is required.
+// Summary patterns match a line of counts using the named groups errors,
+// warnings and issues.
+type ParserConfig struct {
+	// Format reads machine-readable output instead: "eslint-json" for
+	// eslint --format json, or "tsc" for tsc --pretty false
+	Format      string   `yaml:"format,omitempty"`
+	Diagnostics []string `yaml:"diagnostics,omitempty"`
+	Summary     []string `yaml:"summary,omitempty"`
+}
+
+// Named groups parser patterns may use, and the supported output formats
+var (
+	parserFormats    = []string{"eslint-json", "tsc"}
+	diagnosticGroups = []string{"file", "line", "col", "severity", "rule", "message"}
+	summaryGroups    = []string{"errors", "warnings", "issues"}
+)
+
+// IsZero reports whether no parser is declared
+func (p ParserConfig) IsZero() bool {
+	return p.Format == "" && len(p.Diagnostics) == 0 && len(p.Summary) == 0
 }
 
-// DefaultConfig returns the default configuration
+// DefaultConfig returns the default configuration, with the default
+// commands of Node.js projects and those without a marker file
 func DefaultConfig() *Config {
-	return &Config{
+	config := &Config{
 		DefaultTimeout: "30s",
 		MaxParallel:    3,
-		Commands: map[string]Command{
-			"typescript": {
-				Command: "npx",
-				Args:    []string{"tsc", "--noEmit"},
-				Timeout: "30s",
-				Enabled: true,
-			},
-			"lint": {
-				Command: "npx",
-				Args:    []string{"eslint", ".", "--ext", ".ts,.tsx,.js,.jsx"},
-				Timeout: "30s",
-				Enabled: true,
-			},
-			"test": {
-				Command: "npm",
-				Args:    []string{"test"},
-				Timeout: "60s",
-				Enabled: true,
-			},
-			"github_actions": {
-				Command: "github_actions",
-				Args:    []string{},
-				Timeout: "30s",
-				Enabled: false, // Disabled by default, will be auto-enabled if GitHub repo detected
-			},
+		Commands:       make(map[string]Command),
+		History: HistoryConfig{
+			MaxEntries: DefaultHistoryMaxEntries,
+			MaxAge:     "30d",
 		},
 	}
+	
+	for _, def := range defaultCommands {
+		if def.marker == "" || def.marker == NodeMarker {
+			config.Commands[def.name] = def.clone()
+		}
+	}
+	
+	return config
 }
 
 // Load loads configuration from the specified directory
 func Load(dir string) (*Config, error) {
 	configPath := filepath.Join(dir, ".kwatch", "kwatch.yaml")
 	
-	// If config file doesn't exist, return default config
+	// If config file doesn't exist, return the defaults for the project
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return DefaultConfig(), nil
+		return DefaultConfigFor(dir), nil
 	}
 	
 	// Read config file
@@ -134,6 +284,194 @@ func (c *Config) Validate() error {
 				return fmt.Errorf("command %s: invalid timeout: %w", name, err)
 			}
 		}
+		
+		if cmd.Weight < 0 {
+			return fmt.Errorf("command %s: weight must not be negative", name)
+		}
+		
+		if cmd.Weight > c.MaxParallel {
+			return fmt.Errorf("command %s: weight %d exceeds maxParallel %d", name, cmd.Weight, c.MaxParallel)
+		}
+		
+		for _, input := range cmd.Inputs {
+			if err := validateGlob(input); err != nil {
+				return fmt.Errorf("command %s: invalid input %q: %w", name, input, err)
+			}
+		}
+		
+		for _, pattern := range cmd.IncrementalFiles {
+			if err := validateGlob(pattern); err != nil {
+				return fmt.Errorf("command %s: invalid incrementalFiles pattern %q: %w", name, pattern, err)
+			}
+		}
+		
+		for _, pattern := range cmd.Reports {
+			if err := validateGlob(pattern); err != nil {
+				return fmt.Errorf("command %s: invalid reports pattern %q: %w", name, pattern, err)
+			}
+		}
+		
+		if cmd.Retries < 0 {
+			return fmt.Errorf("command %s: retries must not be negative", name)
+		}
+		
+		if cmd.RetryBackoff != "" {
+			if _, err := time.ParseDuration(cmd.RetryBackoff); err != nil {
+				return fmt.Errorf("command %s: invalid retryBackoff: %w", name, err)
+			}
+		}
+		
+		for _, pattern := range cmd.RetryOn.Output {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("command %s: invalid retryOn.output pattern %q: %w", name, pattern, err)
+			}
+		}
+		
+		if err := cmd.Limits.validate(); err != nil {
+			return fmt.Errorf("command %s: %w", name, err)
+		}
+		
+		if err := cmd.Coverage.validate(); err != nil {
+			return fmt.Errorf("command %s: %w", name, err)
+		}
+		
+		for _, pattern := range cmd.Success.Require {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("command %s: invalid success.require pattern %q: %w", name, pattern, err)
+			}
+		}
+		
+		for _, pattern := range cmd.Success.Forbid {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("command %s: invalid success.forbid pattern %q: %w", name, pattern, err)
+			}
+		}
+		
+		if cmd.Parser.Format != "" {
+			known := false
+			for _, format := range parserFormats {
+				if cmd.Parser.Format == format {
+					known = true
+				}
+			}
+			if !known {
+				return fmt.Errorf("command %s: unknown parser format %q, expected one of %s", name, cmd.Parser.Format, strings.Join(parserFormats, ", "))
+			}
+			if len(cmd.Parser.Diagnostics) > 0 || len(cmd.Parser.Summary) > 0 {
+				return fmt.Errorf("command %s: parser format cannot be combined with patterns", name)
+			}
+		}
+		
+		for _, pattern := range cmd.Parser.Diagnostics {
+			if err := validateParserPattern(pattern, diagnosticGroups, "message"); err != nil {
+				return fmt.Errorf("command %s: invalid parser.diagnostics pattern %q: %w", name, pattern, err)
+			}
+		}
+		
+		for _, pattern := range cmd.Parser.Summary {
+			if err := validateParserPattern(pattern, summaryGroups, ""); err != nil {
+				return fmt.Errorf("command %s: invalid parser.summary pattern %q: %w", name, pattern, err)
+			}
+		}
+		
+		for _, dep := range cmd.DependsOn {
+			if dep == name {
+				return fmt.Errorf("command %s: cannot depend on itself", name)
+			}
+			if _, exists := c.Commands[dep]; !exists {
+				return fmt.Errorf("command %s: unknown dependency %s", name, dep)
+			}
+		}
+	}
+	
+	// Validate that dependencies form a DAG
+	if err := c.checkDependencyCycles(); err != nil {
+		return err
+	}
+	
+	// Validate flaky test detection
+	if c.Flaky.MinScore < 0 || c.Flaky.MinScore > 1 {
+		return fmt.Errorf("flaky.minScore must be between 0 and 1")
+	}
+	
+	// Validate trend analysis
+	if c.Trends.Window < 0 || c.Trends.Recent < 0 {
+		return fmt.Errorf("trends: window and recent must not be negative")
+	}
+	if c.Trends.DurationIncrease < 0 || c.Trends.IssueIncrease < 0 {
+		return fmt.Errorf("trends: durationIncrease and issueIncrease must not be negative")
+	}
+	
+	// Validate history retention
+	if c.History.MaxEntries < 0 {
+		return fmt.Errorf("history: maxEntries must not be negative")
+	}
+	if c.History.MaxAge != "" {
+		if _, err := ParseDuration(c.History.MaxAge); err != nil {
+			return fmt.Errorf("history: invalid maxAge: %w", err)
+		}
+	}
+	if c.History.FailuresOnlyAfter != "" {
+		if _, err := ParseDuration(c.History.FailuresOnlyAfter); err != nil {
+			return fmt.Errorf("history: invalid failuresOnlyAfter: %w", err)
+		}
+	}
+	
+	return nil
+}
+
+// checkDependencyCycles returns an error describing the first dependency cycle found
+func (c *Config) checkDependencyCycles() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	
+	state := make(map[string]int)
+	var path []string
+	
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			// Report the cycle starting from where it closes
+			start := 0
+			for i, step := range path {
+				if step == name {
+					start = i
+					break
+				}
+			}
+			cycle := append(append([]string{}, path[start:]...), name)
+			return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		case visited:
+			return nil
+		}
+		
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range c.Commands[name].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	
+	// Visit in sorted order so the reported cycle is deterministic
+	names := make([]string, 0, len(c.Commands))
+	for name := range c.Commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return err
+		}
 	}
 	
 	return nil
@@ -166,6 +504,190 @@ func (c *Config) GetTimeout(cmdName string) time.Duration {
 	return 30 * time.Second
 }
 
+// HistoryRetention returns the history retention settings, falling back
+// to the defaults for unset values. A zero failuresOnlyAfter keeps
+// passing results until they reach maxAge.
+func (c *Config) HistoryRetention() (maxEntries int, maxAge, failuresOnlyAfter time.Duration) {
+	maxEntries = c.History.MaxEntries
+	if maxEntries == 0 {
+		maxEntries = DefaultHistoryMaxEntries
+	}
+	
+	maxAge = DefaultHistoryMaxAge
+	if duration, err := ParseDuration(c.History.MaxAge); err == nil && duration > 0 {
+		maxAge = duration
+	}
+	
+	if duration, err := ParseDuration(c.History.FailuresOnlyAfter); err == nil {
+		failuresOnlyAfter = duration
+	}
+	
+	return maxEntries, maxAge, failuresOnlyAfter
+}
+
+// FlakyMinScore returns the score at which a test counts as known-flaky,
+// falling back to the given default when unset
+func (c *Config) FlakyMinScore(fallback float64) float64 {
+	if c.Flaky.MinScore > 0 {
+		return c.Flaky.MinScore
+	}
+	return fallback
+}
+
+// ParseDuration parses a Go duration, also accepting a whole number of days ("7d")
+func ParseDuration(value string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(value, "d"); found {
+		if count, err := strconv.Atoi(days); err == nil {
+			if count < 0 {
+				return 0, fmt.Errorf("negative duration %q", value)
+			}
+			return time.Duration(count) * 24 * time.Hour, nil
+		}
+	}
+	return time.ParseDuration(value)
+}
+
+// ParseSize parses a byte size: a whole number with an optional K, M, G or
+// T suffix ("512M", "2G"). Suffixes are powers of 1024 and may be written
+// as KB or KiB.
+func ParseSize(value string) (int64, error) {
+	units := []struct {
+		suffix string
+		size   int64
+	}{
+		{"T", 1 << 40},
+		{"G", 1 << 30},
+		{"M", 1 << 20},
+		{"K", 1 << 10},
+	}
+	
+	number := strings.ToUpper(strings.TrimSpace(value))
+	number = strings.TrimSuffix(strings.TrimSuffix(number, "B"), "I")
+	multiplier := int64(1)
+	for _, unit := range units {
+		if trimmed, found := strings.CutSuffix(number, unit.suffix); found {
+			number, multiplier = trimmed, unit.size
+			break
+		}
+	}
+	
+	count, err := strconv.ParseInt(strings.TrimSpace(number), 10, 64)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return count * multiplier, nil
+}
+
+// validate checks the resource limits
+func (l LimitsConfig) validate() error {
+	if l.Memory != "" {
+		if _, err := ParseSize(l.Memory); err != nil {
+			return fmt.Errorf("limits.memory: %w", err)
+		}
+	}
+	if l.CPUTime != "" {
+		if _, err := time.ParseDuration(l.CPUTime); err != nil {
+			return fmt.Errorf("limits.cpuTime: %w", err)
+		}
+	}
+	if l.OpenFiles < 0 || l.Processes < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+	return nil
+}
+
+// validate checks the coverage report settings
+func (c CoverageConfig) validate() error {
+	thresholds := c.MinCoverage
+	if c.Report == "" {
+		if c.Format != "" || thresholds != (CoverageThresholds{}) {
+			return fmt.Errorf("coverage: report is required")
+		}
+		return nil
+	}
+	
+	if c.Format != "" {
+		known := false
+		for _, format := range coverageFormats {
+			if c.Format == format {
+				known = true
+			}
+		}
+		if !known {
+			return fmt.Errorf("coverage: unknown format %q, expected one of %s", c.Format, strings.Join(coverageFormats, ", "))
+		}
+	}
+	
+	for _, minimum := range []float64{thresholds.Lines, thresholds.Branches, thresholds.Functions} {
+		if minimum < 0 || minimum > 100 {
+			return fmt.Errorf("coverage: minCoverage values must be between 0 and 100")
+		}
+	}
+	
+	// Go coverprofiles only have statement coverage, reported as lines
+	if c.Format == "go" && (thresholds.Branches > 0 || thresholds.Functions > 0) {
+		return fmt.Errorf("coverage: go reports have no branch or function coverage, only minCoverage.lines can be set")
+	}
+	return nil
+}
+
+// validateParserPattern checks that a parser pattern compiles and only uses
+// the allowed named groups. If required is set, the pattern must capture
+// that group; otherwise it must capture at least one allowed group.
+func validateParserPattern(pattern string, allowed []string, required string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	
+	found := false
+	for _, group := range re.SubexpNames() {
+		if group == "" {
+			continue
+		}
+		known := false
+		for _, name := range allowed {
+			if group == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown group %q, expected one of %s", group, strings.Join(allowed, ", "))
+		}
+		if required == "" || group == required {
+			found = true
+		}
+	}
+	
+	if !found {
+		if required != "" {
+			return fmt.Errorf("missing named group %q", required)
+		}
+		return fmt.Errorf("missing a named group, expected one of %s", strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// validateGlob checks a slash-separated glob pattern, allowing "**" segments
+func validateGlob(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("empty pattern")
+	}
+	if filepath.IsAbs(pattern) || strings.HasPrefix(pattern, "../") {
+		return fmt.Errorf("pattern must be relative to the project")
+	}
+	for _, part := range strings.Split(pattern, "/") {
+		if part == "**" {
+			continue
+		}
+		if _, err := path.Match(part, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetEnabledCommands returns only the enabled commands
 func (c *Config) GetEnabledCommands() map[string]Command {
 	enabled := make(map[string]Command)
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// NodeMarker marks Node.js projects. Their commands are the defaults of
+// projects in which no other toolchain is detected.
+const NodeMarker = "package.json"
+
+// defaultCommand is a command added to the default configuration of
+// projects that have its marker file
+type defaultCommand struct {
+	name    string
+	marker  string
+	command Command
+}
+
+// defaultCommands are the commands of default configurations. Commands
+// without a marker are part of every default configuration.
+var defaultCommands = []defaultCommand{
+	{name: "typescript", marker: NodeMarker, command: Command{
+		Command: "npx",
+		Args:    []string{"tsc", "--noEmit"},
+		Timeout: "30s",
+		Enabled: true,
+	}},
+	{name: "lint", marker: NodeMarker, command: Command{
+		Command: "npx",
+		Args:    []string{"eslint", ".", "--ext", ".ts,.tsx,.js,.jsx"},
+		Timeout: "30s",
+		Enabled: true,
+	}},
+	{name: "test", marker: NodeMarker, command: Command{
+		Command: "npm",
+		Args:    []string{"test"},
+		Timeout: "60s",
+		Enabled: true,
+	}},
+	{name: "go_build", marker: "go.mod", command: Command{
+		Command: "go",
+		Args:    []string{"build", "./..."},
+		Timeout: "60s",
+		Enabled: true,
+	}},
+	{name: "go_vet", marker: "go.mod", command: Command{
+		Command: "go",
+		Args:    []string{"vet", "./..."},
+		Timeout: "60s",
+		Enabled: true,
+	}},
+	{name: "go_test", marker: "go.mod", command: Command{
+		Command: "go",
+		Args:    []string{"test", "-json", "./..."},
+		Timeout: "120s",
+		Enabled: true,
+	}},
+	{name: "staticcheck", marker: "go.mod", command: Command{
+		Command: "staticcheck",
+		Args:    []string{"-f", "json", "./..."},
+		Timeout: "60s",
+		Enabled: false, // Needs staticcheck to be installed
+	}},
+	{name: "mypy", marker: "pyproject.toml", command: Command{
+		Command: "mypy",
+		Args:    []string{"."},
+		Timeout: "60s",
+		Enabled: true,
+	}},
+	{name: "ruff", marker: "pyproject.toml", command: Command{
+		Command: "ruff",
+		Args:    []string{"check", "--output-format", "json", "."},
+		Timeout: "30s",
+		Enabled: true,
+	}},
+	// pytest writes a JUnit report into its own cache directory for
+	// per-test results
+	{name: "pytest", marker: "pyproject.toml", command: Command{
+		Command: "pytest",
+		Args:    []string{"--junitxml=.pytest_cache/junit.xml"},
+		Timeout: "120s",
+		Enabled: true,
+		Reports: []string{".pytest_cache/junit.xml"},
+	}},
+	// cargo's JSON messages give exact diagnostics
+	{name: "cargo_check", marker: "Cargo.toml", command: Command{
+		Command: "cargo",
+		Args:    []string{"check", "--all-targets", "--message-format=json"},
+		Timeout: "120s",
+		Enabled: true,
+	}},
+	{name: "cargo_clippy", marker: "Cargo.toml", command: Command{
+		Command: "cargo",
+		Args:    []string{"clippy", "--all-targets", "--message-format=json"},
+		Timeout: "120s",
+		Enabled: true,
+	}},
+	{name: "cargo_test", marker: "Cargo.toml", command: Command{
+		Command: "cargo",
+		Args:    []string{"test", "--message-format=json"},
+		Timeout: "300s",
+		Enabled: true,
+	}},
+	{name: "github_actions", command: Command{
+		Command: "github_actions",
+		Args:    []string{},
+		Timeout: "30s",
+		Enabled: false, // Enabled when a GitHub repository is detected
+	}},
+}
+
+// DefaultCommand returns the default command of a name and the marker
+// file of the projects it is configured for by default
+func DefaultCommand(name string) (command Command, marker string, exists bool) {
+	for _, def := range defaultCommands {
+		if def.name == name {
+			return def.clone(), def.marker, true
+		}
+	}
+	return Command{}, "", false
+}
+
+// clone returns the command with its own copies of its lists, so
+// configurations built from it can be changed
+func (d defaultCommand) clone() Command {
+	command := d.command
+	if command.Args != nil {
+		command.Args = append([]string{}, command.Args...)
+	}
+	if command.Reports != nil {
+		command.Reports = append([]string{}, command.Reports...)
+	}
+	return command
+}
+
+// DefaultConfigFor returns the default configuration for the project in
+// dir. Commands are added for each detected toolchain, and projects
+// without a package.json drop the Node.js defaults.
+func DefaultConfigFor(dir string) *Config {
+	config := DefaultConfig()
+
+	detected := false
+	for _, def := range defaultCommands {
+		if def.marker == "" || def.marker == NodeMarker {
+			continue
+		}
+		if !fileExists(filepath.Join(dir, def.marker)) {
+			continue
+		}
+		config.Commands[def.name] = def.clone()
+		detected = true
+	}
+
+	if detected && !fileExists(filepath.Join(dir, NodeMarker)) {
+		for _, def := range defaultCommands {
+			if def.marker == NodeMarker {
+				delete(config.Commands, def.name)
+			}
+		}
+	}
+
+	return config
+}
+
+// fileExists reports whether a regular file exists at path
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
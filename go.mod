@@ -7,6 +7,7 @@ require (
 	github.com/charmbracelet/lipgloss v0.9.1
 	github.com/fsnotify/fsnotify v1.9.0
 	github.com/spf13/cobra v1.8.0
+	golang.org/x/sys v0.20.0
 	golang.org/x/term v0.16.0
 	gopkg.in/yaml.v2 v2.4.0
 )
@@ -26,6 +27,5 @@ require (
 	github.com/rivo/uniseg v0.4.7 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	golang.org/x/sync v0.6.0 // indirect
-	golang.org/x/sys v0.20.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
 )
@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"kwatch/config"
@@ -30,6 +31,13 @@ type JSONRPCResponse struct {
 	Error   *JSONRPCError `json:"error,omitempty"`
 }
 
+// JSONRPCNotification represents a JSON-RPC 2.0 notification
+type JSONRPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
 // JSONRPCError represents a JSON-RPC 2.0 error
 type JSONRPCError struct {
 	Code    int         `json:"code"`
@@ -45,6 +53,7 @@ type MCPServer struct {
 	writer    io.Writer
 	ctx       context.Context
 	cancel    context.CancelFunc
+	writeMutex sync.Mutex
 }
 
 // InitializeParams represents MCP initialization parameters
@@ -109,7 +118,7 @@ func NewMCPServer(workDir string) *MCPServer {
 	kwatchConfig, err := config.Load(workDir)
 	if err != nil {
 		// Fall back to default config if loading fails
-		kwatchConfig = config.DefaultConfig()
+		kwatchConfig = config.DefaultConfigFor(workDir)
 	}
 	
 	// Create runner configuration with shorter timeouts for MCP
@@ -244,8 +253,8 @@ func (s *MCPServer) handleToolsList(req JSONRPCRequest) error {
 				Properties: map[string]interface{}{
 					"command": map[string]interface{}{
 						"type":        "string",
-						"description": "Specific command to run: 'all', 'tsc', 'lint', or 'test'",
-						"enum":        []string{"all", "tsc", "lint", "test"},
+						"description": "Specific command to run: 'all' or a command name such as 'tsc', 'lint' or 'test'",
+						"enum":        append([]string{"all"}, s.runner.CommandNames()...),
 						"default":     "all",
 					},
 				},
@@ -264,8 +273,47 @@ func (s *MCPServer) handleToolsList(req JSONRPCRequest) error {
 					},
 					"filter": map[string]interface{}{
 						"type":        "string",
-						"description": "Filter by command type: 'tsc', 'lint', or 'test'",
-						"enum":        []string{"tsc", "lint", "test"},
+						"description": "Filter by command type, such as 'tsc', 'lint' or 'test'",
+						"enum":        s.runner.CommandNames(),
+					},
+				},
+			},
+		},
+		{
+			Name:        "get_flaky_tests",
+			Description: "List tests that both passed and failed on the same code (same git tree), with a flakiness score from 0 to 1",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"min_score": map[string]interface{}{
+						"type":        "number",
+						"description": "Only return tests with at least this flakiness score",
+						"default":     0,
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of tests to return",
+						"default":     20,
+						"minimum":     0,
+					},
+				},
+			},
+		},
+		{
+			Name:        "get_runs",
+			Description: "List recent runs, each grouping the commands started together by one trigger (file change, manual, API, schedule or MCP) with the git commit they ran on. Pass run_id to get one run with its full results",
+			InputSchema: ToolSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"run_id": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of a run to return with its full results",
+					},
+					"limit": map[string]interface{}{
+						"type":        "number",
+						"description": "Maximum number of runs to list, newest first",
+						"default":     10,
+						"minimum":     0,
 					},
 				},
 			},
@@ -284,12 +332,21 @@ func (s *MCPServer) handleToolsCall(req JSONRPCRequest) error {
 	var params struct {
 		Name      string                 `json:"name"`
 		Arguments map[string]interface{} `json:"arguments"`
+		Meta      struct {
+			ProgressToken interface{} `json:"progressToken"`
+		} `json:"_meta"`
 	}
 
 	if err := json.Unmarshal(req.Params, &params); err != nil {
 		return s.sendError(req.ID, -32602, "Invalid params", err)
 	}
 
+	// Report command output as progress if the client asked for it
+	if params.Meta.ProgressToken != nil {
+		unsubscribe := s.streamProgress(params.Meta.ProgressToken)
+		defer unsubscribe()
+	}
+
 	switch params.Name {
 	case "get_build_status":
 		return s.handleGetBuildStatus(req.ID, params.Arguments)
@@ -297,6 +354,10 @@ func (s *MCPServer) handleToolsCall(req JSONRPCRequest) error {
 		return s.handleRunCommands(req.ID, params.Arguments)
 	case "get_command_history":
 		return s.handleGetCommandHistory(req.ID, params.Arguments)
+	case "get_flaky_tests":
+		return s.handleGetFlakyTests(req.ID, params.Arguments)
+	case "get_runs":
+		return s.handleGetRuns(req.ID, params.Arguments)
 	default:
 		return s.sendError(req.ID, -32602, "Unknown tool", map[string]interface{}{
 			"tool": params.Name,
@@ -315,18 +376,19 @@ func (s *MCPServer) handleGetBuildStatus(id interface{}, args map[string]interfa
 	ctx, cancel := context.WithTimeout(context.Background(), 12 * time.Second)
 	defer cancel()
 	
-	results := s.runner.RunAll(ctx)
+	run := s.runner.RunAll(ctx, runner.TriggerMCP)
 
 	var content string
 	
 	if format == "compact" {
-		content = runner.FormatCompactStatus(results)
+		content = runner.FormatCompactStatus(run.Commands)
 	} else {
 		// Format as detailed JSON
 		status := map[string]interface{}{
 			"directory": s.workDir,
 			"timestamp": time.Now().Format(time.RFC3339),
-			"commands":  formatCommandResults(results),
+			"run_id":    run.ID,
+			"commands":  formatCommandResults(run.Commands),
 		}
 		
 		jsonBytes, err := json.MarshalIndent(status, "", "  ")
@@ -361,51 +423,26 @@ func (s *MCPServer) handleRunCommands(id interface{}, args map[string]interface{
 	defer cancel()
 	
 	var results map[runner.CommandType]runner.CommandResult
+	var run runner.RunResult
 
 	switch command {
 	case "all":
-		results = s.runner.RunAll(ctx)
-	case "tsc":
-		cmd := runner.Command{
-			Type:    runner.TypescriptCheck,
-			Command: "npx",
-			Args:    []string{"tsc", "--noEmit"},
-			Timeout: 15 * time.Second,
-		}
-		result := s.runner.RunCommand(ctx, cmd)
-		results = map[runner.CommandType]runner.CommandResult{
-			runner.TypescriptCheck: result,
-		}
-	case "lint":
-		cmd := runner.Command{
-			Type:    runner.LintCheck,
-			Command: "npx",
-			Args:    []string{"eslint", ".", "--ext", ".ts,.tsx,.js,.jsx"},
-			Timeout: 15 * time.Second,
-		}
-		result := s.runner.RunCommand(ctx, cmd)
-		results = map[runner.CommandType]runner.CommandResult{
-			runner.LintCheck: result,
-		}
-	case "test":
-		cmd := runner.Command{
-			Type:    runner.TestRunner,
-			Command: "npm",
-			Args:    []string{"test"},
-			Timeout: 20 * time.Second,
-		}
-		result := s.runner.RunCommand(ctx, cmd)
-		results = map[runner.CommandType]runner.CommandResult{
-			runner.TestRunner: result,
-		}
+		run = s.runner.RunAll(ctx, runner.TriggerMCP)
+		results = run.Commands
 	default:
-		return s.sendError(id, -32602, "Invalid command", map[string]interface{}{
-			"command": command,
-		})
+		cmd, exists := s.runner.ResolveCommand(command)
+		if !exists {
+			return s.sendError(id, -32602, "Invalid command", map[string]interface{}{
+				"command": command,
+			})
+		}
+		run = s.runner.RunOne(ctx, runner.TriggerMCP, cmd)
+		results = run.Commands
 	}
 
 	response := map[string]interface{}{
 		"timestamp": time.Now().Format(time.RFC3339),
+		"run_id":    run.ID,
 		"command":   command,
 		"results":   formatCommandResults(results),
 	}
@@ -446,21 +483,14 @@ func (s *MCPServer) handleGetCommandHistory(id interface{}, args map[string]inte
 
 	// Filter if requested
 	if filter != "" {
+		cmdType := runner.CommandType(filter)
+		if checker, exists := runner.FindChecker(filter); exists {
+			cmdType = checker.Type()
+		}
 		var filtered []runner.CommandResult
 		for _, entry := range history {
-			switch filter {
-			case "tsc":
-				if strings.Contains(entry.Command, "tsc") {
-					filtered = append(filtered, entry)
-				}
-			case "lint":
-				if strings.Contains(entry.Command, "eslint") || strings.Contains(entry.Command, "lint") {
-					filtered = append(filtered, entry)
-				}
-			case "test":
-				if strings.Contains(entry.Command, "test") {
-					filtered = append(filtered, entry)
-				}
+			if entry.CommandType() == cmdType {
+				filtered = append(filtered, entry)
 			}
 		}
 		history = filtered
@@ -496,36 +526,190 @@ func (s *MCPServer) handleGetCommandHistory(id interface{}, args map[string]inte
 	return s.sendResponse(id, result)
 }
 
+// handleGetFlakyTests implements the get_flaky_tests tool
+func (s *MCPServer) handleGetFlakyTests(id interface{}, args map[string]interface{}) error {
+	limit := 20
+	if l, ok := args["limit"].(float64); ok {
+		limit = int(l)
+	}
+	if limit < 0 {
+		return s.sendError(id, -32602, "Invalid limit", map[string]interface{}{
+			"limit": limit,
+		})
+	}
+
+	minScore := 0.0
+	if m, ok := args["min_score"].(float64); ok {
+		minScore = m
+	}
+
+	tests := []runner.FlakyTest{}
+	for _, test := range s.runner.History().Flakiness() {
+		if test.Score >= minScore {
+			tests = append(tests, test)
+		}
+	}
+	if len(tests) > limit {
+		tests = tests[:limit]
+	}
+
+	response := map[string]interface{}{
+		"count": len(tests),
+		"tests": tests,
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	var content string
+	if err != nil {
+		content = fmt.Sprintf("Error formatting flaky tests: %v", err)
+	} else {
+		content = string(jsonBytes)
+	}
+
+	result := map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": content,
+			},
+		},
+	}
+
+	return s.sendResponse(id, result)
+}
+
+// handleGetRuns implements the get_runs tool
+func (s *MCPServer) handleGetRuns(id interface{}, args map[string]interface{}) error {
+	var response map[string]interface{}
+
+	if runID, ok := args["run_id"].(string); ok && runID != "" {
+		run, exists := s.runner.Run(runID)
+		if !exists {
+			return s.sendError(id, -32602, "Unknown run", map[string]interface{}{
+				"run_id": runID,
+			})
+		}
+		summary := formatRun(run)
+		summary["commands"] = formatCommandResults(run.Commands)
+		response = map[string]interface{}{
+			"run": summary,
+		}
+	} else {
+		limit := 10
+		if l, ok := args["limit"].(float64); ok {
+			limit = int(l)
+		}
+		if limit < 0 {
+			return s.sendError(id, -32602, "Invalid limit", map[string]interface{}{
+				"limit": limit,
+			})
+		}
+
+		runs := s.runner.Runs()
+		if len(runs) > limit {
+			runs = runs[:limit]
+		}
+		summaries := make([]map[string]interface{}, len(runs))
+		for i, run := range runs {
+			summaries[i] = formatRun(run)
+		}
+		response = map[string]interface{}{
+			"count": len(summaries),
+			"runs":  summaries,
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(response, "", "  ")
+	var content string
+	if err != nil {
+		content = fmt.Sprintf("Error formatting runs: %v", err)
+	} else {
+		content = string(jsonBytes)
+	}
+
+	result := map[string]interface{}{
+		"content": []map[string]interface{}{
+			{
+				"type": "text",
+				"text": content,
+			},
+		},
+	}
+
+	return s.sendResponse(id, result)
+}
+
+// formatRun summarizes a run with the status of each of its commands
+func formatRun(run runner.RunResult) map[string]interface{} {
+	statuses := make(map[string]runner.ResultStatus, len(run.Commands))
+	for cmdType, result := range run.Commands {
+		statuses[string(cmdType)] = result.State()
+	}
+
+	summary := map[string]interface{}{
+		"id":        run.ID,
+		"trigger":   run.Trigger,
+		"timestamp": run.Timestamp.Format(time.RFC3339),
+		"duration":  run.Duration.String(),
+		"passed":    run.Passed(),
+		"statuses":  statuses,
+	}
+	if run.GitSHA != "" {
+		summary["git_sha"] = run.GitSHA
+		summary["dirty"] = run.Dirty
+	}
+	if len(run.ChangedFiles) > 0 {
+		summary["changed_files"] = run.ChangedFiles
+	}
+	return summary
+}
+
 // formatCommandResults formats command results for JSON output
 func formatCommandResults(results map[runner.CommandType]runner.CommandResult) map[string]interface{} {
 	formatted := make(map[string]interface{})
 	
-	cmdNames := map[runner.CommandType]string{
-		runner.TypescriptCheck: "tsc",
-		runner.LintCheck:       "lint",
-		runner.TestRunner:      "test",
-	}
-
 	for cmdType, result := range results {
-		name := cmdNames[cmdType]
-		if name == "" {
-			name = string(cmdType)
-		}
+		name := cmdType.ShortName()
 
 		resultData := map[string]interface{}{
 			"passed":      result.Passed,
+			"status":      result.State(),
 			"issue_count": result.IssueCount,
 			"file_count":  result.FileCount,
 			"duration":    result.Duration.String(),
 			"timestamp":   result.Timestamp.Format(time.RFC3339),
 		}
 		
+		// Add structured diagnostics so clients can see what the issues are
+		if len(result.Diagnostics) > 0 {
+			resultData["diagnostics"] = result.Diagnostics
+			resultData["error_count"] = result.ErrorCount
+			resultData["warning_count"] = result.WarningCount
+			resultData["fixable_count"] = result.FixableCount
+		}
+		
 		// Add test-specific fields if this is a test result
-		if cmdType == runner.TestRunner {
+		if runner.IsTestCommand(cmdType) {
 			resultData["total_tests"] = result.TotalTests
 			resultData["passed_tests"] = result.PassedTests
 			resultData["failed_tests"] = result.FailedTests
 		}
+		if result.SkippedTests > 0 {
+			resultData["skipped_tests"] = result.SkippedTests
+		}
+		if result.LimitExceeded != "" {
+			resultData["limit_exceeded"] = result.LimitExceeded
+			resultData["error"] = result.Error
+		}
+		if result.Retried() {
+			resultData["attempts"] = result.Attempts
+		}
+		if result.Coverage != nil {
+			resultData["coverage"] = result.Coverage
+		}
+		if len(result.TestCases) > 0 {
+			resultData["test_cases"] = result.TestCases
+		}
 		
 		formatted[name] = resultData
 	}
@@ -533,6 +717,29 @@ func formatCommandResults(results map[runner.CommandType]runner.CommandResult) m
 	return formatted
 }
 
+// streamProgress sends every output line produced while a tool runs as a
+// notifications/progress message for the given token
+func (s *MCPServer) streamProgress(token interface{}) (unsubscribe func()) {
+	var mutex sync.Mutex
+	progress := 0
+
+	return s.runner.Subscribe(func(line runner.OutputLine) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		progress++
+		s.writeMessage(JSONRPCNotification{
+			JSONRPC: "2.0",
+			Method:  "notifications/progress",
+			Params: map[string]interface{}{
+				"progressToken": token,
+				"progress":      progress,
+				"message":       fmt.Sprintf("[%s] %s", line.Command, line.Text),
+			},
+		})
+	})
+}
+
 // sendResponse sends a JSON-RPC success response
 func (s *MCPServer) sendResponse(id interface{}, result interface{}) error {
 	response := JSONRPCResponse{
@@ -561,6 +768,10 @@ func (s *MCPServer) sendError(id interface{}, code int, message string, data int
 
 // writeMessage writes a JSON-RPC message to stdout
 func (s *MCPServer) writeMessage(message interface{}) error {
+	// Progress notifications are written from running commands
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
 	jsonBytes, err := json.Marshal(message)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "MCP: Error marshaling message: %v\n", err)
@@ -0,0 +1,247 @@
+package runner
+
+import (
+	"kwatch/config"
+)
+
+// builtinChecker is a checker described by its fields
+type builtinChecker struct {
+	cmdType   CommandType
+	kind      CheckerKind
+	labels    Labels
+	defaults  Defaults
+	watch     []string
+	testCases bool
+	parse     func(parser *Parser, output string, result *CommandResult)
+}
+
+func (c *builtinChecker) Type() CommandType    { return c.cmdType }
+func (c *builtinChecker) Kind() CheckerKind    { return c.kind }
+func (c *builtinChecker) Labels() Labels       { return c.labels }
+func (c *builtinChecker) Defaults() Defaults   { return c.defaults }
+func (c *builtinChecker) WatchGlobs() []string { return c.watch }
+func (c *builtinChecker) ReportsTestCases() bool {
+	return c.testCases
+}
+
+// Parse parses the output with the checker's parser, or generically when
+// it has none
+func (c *builtinChecker) Parse(parser *Parser, output string, result *CommandResult) {
+	if c.parse == nil {
+		parseGeneric(parser, output, result)
+		return
+	}
+	c.parse(parser, output, result)
+}
+
+// Files the built-in checkers watch
+var (
+	nodeSources   = []string{"**/*.ts", "**/*.tsx", "**/*.js", "**/*.jsx", "**/*.vue", "**/package.json"}
+	goSources     = []string{"**/*.go", "**/go.mod", "**/go.sum"}
+	pythonSources = []string{"**/*.py", "**/*.pyi", "**/pyproject.toml", "**/setup.cfg"}
+	rustSources   = []string{"**/*.rs", "**/Cargo.toml", "**/Cargo.lock"}
+)
+
+// watching returns the source globs followed by extra files
+func watching(sources []string, files ...string) []string {
+	return append(append([]string(nil), sources...), files...)
+}
+
+func init() {
+	// Registration order is display order
+	for _, checker := range []*builtinChecker{
+		{
+			cmdType:  TypescriptCheck,
+			kind:     KindCheck,
+			labels:   Labels{Name: "TypeScript", Short: "tsc", Symbol: "TSC"},
+			defaults: defaultsOf(TypescriptCheck),
+			watch:    watching(nodeSources, "**/tsconfig.json", "**/jsconfig.json"),
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				_, diagnostics := parser.ParseTypeScriptOutput(output)
+				setParsedDiagnostics(result, diagnostics)
+			},
+		},
+		{
+			cmdType:  LintCheck,
+			kind:     KindLint,
+			labels:   Labels{Name: "Lint", Short: "lint", Symbol: "LINT", Aliases: []string{"eslint"}},
+			defaults: defaultsOf(LintCheck),
+			watch:    watching(nodeSources, "**/eslint.config.js", "**/.eslintrc.js", "**/.eslintrc.json"),
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				_, diagnostics := parser.ParseLintOutput(output)
+				setParsedDiagnostics(result, diagnostics)
+				if len(diagnostics) == 0 {
+					result.IssueCount = parser.CountLintIssues(output)
+				}
+				// A failed lint always has an issue, even when its output
+				// could not be read
+				if result.IssueCount == 0 && result.ExitCode != 0 {
+					result.IssueCount = 1
+				}
+			},
+		},
+		{
+			cmdType:  TestRunner,
+			kind:     KindTest,
+			labels:   Labels{Name: "Test", Short: "test", Symbol: "TEST"},
+			defaults: defaultsOf(TestRunner),
+			watch:    watching(nodeSources, "**/jest.config.js", "**/vite.config.js"),
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				setTestCounts(result, parser.ParseTestOutput(output))
+			},
+		},
+		{
+			cmdType:  GoBuild,
+			kind:     KindCheck,
+			labels:   Labels{Name: "Go Build", Short: "go_build", Symbol: "BUILD"},
+			defaults: defaultsOf(GoBuild),
+			watch:    goSources,
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				setParsedDiagnostics(result, parser.ParseGoOutput(output, "go build"))
+			},
+		},
+		{
+			cmdType:  GoVet,
+			kind:     KindLint,
+			labels:   Labels{Name: "Go Vet", Short: "go_vet", Symbol: "VET"},
+			defaults: defaultsOf(GoVet),
+			watch:    goSources,
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				setParsedDiagnostics(result, parser.ParseGoOutput(output, "go vet"))
+			},
+		},
+		{
+			cmdType:   GoTest,
+			kind:      KindTest,
+			labels:    Labels{Name: "Go Test", Short: "go_test", Symbol: "GOTEST"},
+			defaults:  defaultsOf(GoTest),
+			watch:     goSources,
+			testCases: true,
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				report := parser.ParseGoTestOutput(output)
+				setTestReport(result, report, testResultFromCases(report.Cases))
+			},
+		},
+		{
+			cmdType:  Staticcheck,
+			kind:     KindLint,
+			labels:   Labels{Name: "Staticcheck", Short: "staticcheck", Symbol: "SC"},
+			defaults: defaultsOf(Staticcheck),
+			watch:    goSources,
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				setParsedDiagnostics(result, parser.ParseStaticcheckOutput(output))
+			},
+		},
+		{
+			cmdType:  Mypy,
+			kind:     KindCheck,
+			labels:   Labels{Name: "Mypy", Short: "mypy", Symbol: "MYPY"},
+			defaults: defaultsOf(Mypy),
+			watch:    watching(pythonSources, "**/mypy.ini"),
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				// Notes do not count as issues
+				diagnostics := parser.ParseMypyOutput(output)
+				result.setDiagnostics(diagnostics)
+				result.IssueCount, _ = CountDiagnostics(diagnostics)
+			},
+		},
+		{
+			cmdType:  Ruff,
+			kind:     KindLint,
+			labels:   Labels{Name: "Ruff", Short: "ruff", Symbol: "RUFF"},
+			defaults: defaultsOf(Ruff),
+			watch:    watching(pythonSources, "**/ruff.toml"),
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				setParsedDiagnostics(result, parser.ParseRuffOutput(output))
+			},
+		},
+		{
+			cmdType:  Pytest,
+			kind:     KindTest,
+			labels:   Labels{Name: "Pytest", Short: "pytest", Symbol: "PYTEST"},
+			defaults: defaultsOf(Pytest),
+			watch:    pythonSources,
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				setTestCounts(result, parser.ParsePytestOutput(output))
+			},
+		},
+		{
+			cmdType:  CargoCheck,
+			kind:     KindCheck,
+			labels:   Labels{Name: "Cargo Check", Short: "cargo_check", Symbol: "CHECK"},
+			defaults: defaultsOf(CargoCheck),
+			watch:    rustSources,
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				setParsedDiagnostics(result, parser.ParseCargoOutput(output, "cargo check"))
+			},
+		},
+		{
+			cmdType:  CargoClippy,
+			kind:     KindLint,
+			labels:   Labels{Name: "Clippy", Short: "cargo_clippy", Symbol: "CLIPPY", Aliases: []string{"clippy"}},
+			defaults: defaultsOf(CargoClippy),
+			watch:    rustSources,
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				setParsedDiagnostics(result, parser.ParseCargoOutput(output, "cargo clippy"))
+			},
+		},
+		{
+			cmdType:   CargoTest,
+			kind:      KindTest,
+			labels:    Labels{Name: "Cargo Test", Short: "cargo_test", Symbol: "CARGOTEST"},
+			defaults:  defaultsOf(CargoTest),
+			watch:     rustSources,
+			testCases: true,
+			parse: func(parser *Parser, output string, result *CommandResult) {
+				report, testResult := parser.ParseCargoTestOutput(output)
+				setTestReport(result, report, testResult)
+			},
+		},
+		{
+			// Runs through the GitHub API rather than a process
+			cmdType:  GitHubActions,
+			kind:     KindCI,
+			labels:   Labels{Name: "GitHub Actions", Short: "github", Symbol: "GH", Aliases: []string{"gh"}},
+			defaults: defaultsOf(GitHubActions),
+		},
+	} {
+		Register(checker)
+	}
+}
+
+// defaultsOf returns the checker defaults of a command type from the
+// default configuration
+func defaultsOf(cmdType CommandType) Defaults {
+	command, marker, _ := config.DefaultCommand(string(cmdType))
+	return Defaults{Marker: marker, Command: command}
+}
+
+// setParsedDiagnostics sets a result's diagnostics, each counting as an issue
+func setParsedDiagnostics(result *CommandResult, diagnostics []Diagnostic) {
+	result.setDiagnostics(diagnostics)
+	result.IssueCount = len(diagnostics)
+}
+
+// setTestCounts sets a result's test counts, with failed tests as issues
+func setTestCounts(result *CommandResult, testResult TestResult) {
+	result.IssueCount = testResult.FailedTests
+	result.TotalTests = testResult.TotalTests
+	result.PassedTests = testResult.PassedTests
+	result.FailedTests = testResult.FailedTests
+	result.SkippedTests = testResult.SkippedTests
+}
+
+// setTestReport sets a result's individual tests and counts, with failed
+// tests and build errors as issues
+func setTestReport(result *CommandResult, report TestReport, testResult TestResult) {
+	setTestCounts(result, testResult)
+	result.TestCases = report.Cases
+	result.Packages = report.Packages
+	result.setDiagnostics(report.Diagnostics)
+	result.IssueCount += len(report.Diagnostics)
+}
+
+// parseGeneric counts the issues of commands without a parser of their own
+func parseGeneric(parser *Parser, output string, result *CommandResult) {
+	_, result.IssueCount = parser.ParseGenericOutput(output)
+}
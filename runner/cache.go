@@ -0,0 +1,205 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// maxCachedResults is how many results the cache keeps per command. The
+// least recently used are evicted first.
+const maxCachedResults = 32
+
+// toolLockfiles pin the versions of project-local tools. Their contents
+// are part of every cache key, so upgrading a tool invalidates the cache.
+var toolLockfiles = []string{
+	"package-lock.json",
+	"npm-shrinkwrap.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"bun.lockb",
+}
+
+// ResultCache stores passing results keyed by a hash of everything that
+// can influence them, so an unchanged command does not need to run again.
+// Each command's results are kept in a directory of their own.
+type ResultCache struct {
+	dir string
+}
+
+// CacheDir returns the result cache directory for a project
+func CacheDir(workingDir string) string {
+	return filepath.Join(workingDir, ".kwatch", "cache")
+}
+
+// OpenResultCache opens the result cache in dir. The directory is created
+// when the first result is stored.
+func OpenResultCache(dir string) (*ResultCache, error) {
+	return &ResultCache{dir: dir}, nil
+}
+
+// commandDir returns the directory holding a command's cached results
+func (c *ResultCache) commandDir(cmdType CommandType) string {
+	return filepath.Join(c.dir, url.PathEscape(string(cmdType)))
+}
+
+// Get returns the cached result of a command for a key
+func (c *ResultCache) Get(cmdType CommandType, key string) (CommandResult, bool) {
+	var result CommandResult
+
+	path := filepath.Join(c.commandDir(cmdType), key+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return result, false
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, false
+	}
+
+	// Mark the entry as used so it is evicted last
+	now := time.Now()
+	os.Chtimes(path, now, now)
+	return result, true
+}
+
+// Put stores a result of a command under a key, evicting the command's
+// least recently used results beyond maxCachedResults
+func (c *ResultCache) Put(cmdType CommandType, key string, result CommandResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode cached result: %w", err)
+	}
+
+	dir := c.commandDir(cmdType)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	path := filepath.Join(dir, key+".json")
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("failed to write cached result: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write cached result: %w", err)
+	}
+	return c.evict(dir)
+}
+
+// evict removes the least recently used results in a command's directory
+// beyond maxCachedResults
+func (c *ResultCache) evict(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	type cached struct {
+		path string
+		used time.Time
+	}
+	var results []cached
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		results = append(results, cached{filepath.Join(dir, entry.Name()), info.ModTime()})
+	}
+	if len(results) <= maxCachedResults {
+		return nil
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].used.After(results[j].used)
+	})
+	for _, result := range results[maxCachedResults:] {
+		if err := os.Remove(result.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to evict cached result: %w", err)
+		}
+	}
+	return nil
+}
+
+// CacheKey hashes a command's definition, how its output is parsed, the
+// tools it runs and the contents of its declared input files. Commands
+// without inputs cannot be cached and get an empty key.
+func CacheKey(workingDir string, command Command, parser string) (string, error) {
+	if len(command.Inputs) == 0 {
+		return "", nil
+	}
+
+	hash := sha256.New()
+
+	// Command definition, leaving out what only identifies this run
+	command.ChangedFiles = nil
+	command.RunRef = ""
+	definition, err := json.Marshal(command)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode command: %w", err)
+	}
+	fmt.Fprintf(hash, "command %s\n", definition)
+	fmt.Fprintf(hash, "parser %q\n", parser)
+
+	// Tool versions: the executable itself and the project's lockfiles
+	if executable, err := resolveExecutable(workingDir, command.Command); err == nil {
+		if info, err := os.Stat(executable); err == nil {
+			fmt.Fprintf(hash, "tool %q %d %d\n", executable, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	for _, lockfile := range toolLockfiles {
+		if sum, err := hashFile(filepath.Join(workingDir, lockfile)); err == nil {
+			fmt.Fprintf(hash, "lockfile %q %s\n", lockfile, sum)
+		}
+	}
+
+	// Input file contents
+	files, err := ExpandGlobs(workingDir, command.Inputs)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand inputs: %w", err)
+	}
+	for _, file := range files {
+		sum, err := hashFile(filepath.Join(workingDir, filepath.FromSlash(file)))
+		if err != nil {
+			return "", fmt.Errorf("failed to hash input %s: %w", file, err)
+		}
+		fmt.Fprintf(hash, "input %q %s\n", file, sum)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// resolveExecutable finds the file a command name runs
+func resolveExecutable(workingDir, name string) (string, error) {
+	if filepath.IsAbs(name) {
+		return name, nil
+	}
+	if filepath.Base(name) != name {
+		return filepath.Join(workingDir, name), nil
+	}
+	return exec.LookPath(name)
+}
+
+// hashFile returns the hex SHA-256 of a file's contents
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,190 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// cargoSummaryMessage matches compiler messages that only summarize
+// others, such as "aborting due to 2 previous errors"
+var cargoSummaryMessage = regexp.MustCompile(`^(aborting due to|\d+ warnings? emitted|.* generated \d+ warnings?)`)
+
+// libtestResultPattern matches a test outcome line: "test tests::adds ... ok"
+var libtestResultPattern = regexp.MustCompile(`^test (.+) \.\.\. (ok|FAILED|ignored)\b`)
+
+// libtestSummaryPattern matches the summary of one test binary:
+// "test result: FAILED. 1 passed; 1 failed; 0 ignored; ..."
+var libtestSummaryPattern = regexp.MustCompile(`^test result: \w+\. (\d+) passed; (\d+) failed; (\d+) ignored`)
+
+// libtestOutputPattern matches the header of a failed test's captured output
+var libtestOutputPattern = regexp.MustCompile(`^---- (.+) std(?:out|err) ----$`)
+
+// cargoMessage is one line of cargo --message-format=json output
+type cargoMessage struct {
+	Reason  string               `json:"reason"`
+	Message cargoCompilerMessage `json:"message"`
+}
+
+// cargoCompilerMessage is the diagnostic of a compiler-message record
+type cargoCompilerMessage struct {
+	Message string `json:"message"`
+	Level   string `json:"level"`
+	Code    *struct {
+		Code string `json:"code"`
+	} `json:"code"`
+	Spans    []cargoSpan            `json:"spans"`
+	Children []cargoCompilerMessage `json:"children"`
+}
+
+// cargoSpan is a source location of a compiler message
+type cargoSpan struct {
+	FileName                string `json:"file_name"`
+	LineStart               int    `json:"line_start"`
+	ColumnStart             int    `json:"column_start"`
+	IsPrimary               bool   `json:"is_primary"`
+	SuggestionApplicability string `json:"suggestion_applicability"`
+}
+
+// ParseCargoOutput parses the compiler-message records of cargo
+// --message-format=json output. Messages reported for several targets
+// of the same crate are only counted once.
+func (p *Parser) ParseCargoOutput(output, tool string) []Diagnostic {
+	var diagnostics []Diagnostic
+	seen := make(map[string]bool)
+
+	for _, line := range strings.Split(output, "\n") {
+		var record cargoMessage
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &record) != nil {
+			continue
+		}
+		if record.Reason != "compiler-message" {
+			continue
+		}
+
+		message := record.Message
+		severity := SeverityError
+		switch {
+		case message.Level == "warning":
+			severity = SeverityWarning
+		case !strings.HasPrefix(message.Level, "error"):
+			continue
+		}
+		if len(message.Spans) == 0 && cargoSummaryMessage.MatchString(message.Message) {
+			continue
+		}
+
+		diagnostic := Diagnostic{
+			Severity: severity,
+			Message:  message.Message,
+			Tool:     tool,
+			Fixable:  message.machineApplicable(),
+		}
+		if message.Code != nil {
+			diagnostic.Rule = message.Code.Code
+		}
+		for _, span := range message.Spans {
+			if span.IsPrimary {
+				diagnostic.File = span.FileName
+				diagnostic.Line = span.LineStart
+				diagnostic.Column = span.ColumnStart
+				break
+			}
+		}
+
+		key := fmt.Sprintf("%s:%d:%d:%s:%s", diagnostic.File, diagnostic.Line, diagnostic.Column, diagnostic.Rule, diagnostic.Message)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// machineApplicable reports whether the message or one of its children
+// carries a suggestion cargo fix can apply
+func (m cargoCompilerMessage) machineApplicable() bool {
+	for _, span := range m.Spans {
+		if span.SuggestionApplicability == "MachineApplicable" {
+			return true
+		}
+	}
+	for _, child := range m.Children {
+		if child.machineApplicable() {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCargoTestOutput parses cargo test output: compile errors from the
+// JSON compiler messages, and test outcomes from libtest's plain output.
+// Counts are summed over the summary line of every test binary.
+func (p *Parser) ParseCargoTestOutput(output string) (TestReport, TestResult) {
+	report := TestReport{Diagnostics: p.ParseCargoOutput(output, "cargo test")}
+	var result TestResult
+
+	failures := make(map[string]*strings.Builder)
+	var current *strings.Builder
+	for _, line := range strings.Split(stripANSI(output), "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if matches := libtestResultPattern.FindStringSubmatch(line); matches != nil {
+			report.Cases = append(report.Cases, TestCase{
+				Name:   matches[1],
+				Status: libtestStatus(matches[2]),
+			})
+			continue
+		}
+
+		if matches := libtestSummaryPattern.FindStringSubmatch(line); matches != nil {
+			passed, _ := strconv.Atoi(matches[1])
+			failed, _ := strconv.Atoi(matches[2])
+			ignored, _ := strconv.Atoi(matches[3])
+			result.PassedTests += passed
+			result.FailedTests += failed
+			result.SkippedTests += ignored
+			current = nil
+			continue
+		}
+
+		// Captured output of failed tests is printed after all outcomes
+		if matches := libtestOutputPattern.FindStringSubmatch(line); matches != nil {
+			current = &strings.Builder{}
+			failures[matches[1]] = current
+			continue
+		}
+		if line == "failures:" || line == "" {
+			current = nil
+			continue
+		}
+		if current != nil {
+			current.WriteString(line + "\n")
+		}
+	}
+
+	for i := range report.Cases {
+		if output, exists := failures[report.Cases[i].Name]; exists {
+			report.Cases[i].Message = strings.TrimSpace(output.String())
+		}
+	}
+
+	result.TotalTests = result.PassedTests + result.FailedTests + result.SkippedTests
+	result.Passed = result.FailedTests == 0
+	return report, result
+}
+
+// libtestStatus maps a libtest outcome to a test status
+func libtestStatus(outcome string) TestCaseStatus {
+	switch outcome {
+	case "ok":
+		return TestCasePassed
+	case "ignored":
+		return TestCaseSkipped
+	default:
+		return TestCaseFailed
+	}
+}
@@ -0,0 +1,309 @@
+package runner
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"kwatch/config"
+)
+
+// CheckerKind is what a checker does, which decides how its results are
+// shown and whether it runs when files change
+type CheckerKind string
+
+const (
+	// KindCheck builds or type-checks code
+	KindCheck CheckerKind = "check"
+	// KindLint lints code
+	KindLint CheckerKind = "lint"
+	// KindTest runs tests and reports test counts
+	KindTest CheckerKind = "test"
+	// KindCI reports the jobs of a remote CI service
+	KindCI CheckerKind = "ci"
+)
+
+// RunsOnChange reports whether checkers of the kind run when watched files
+// change. Tests and CI only run on demand.
+func (k CheckerKind) RunsOnChange() bool {
+	return k == KindCheck || k == KindLint
+}
+
+// Labels are the names a checker is shown and referred to by
+type Labels struct {
+	// Name is the human-readable name, as in "TypeScript"
+	Name string
+	// Short is the name in JSON status output and the name commands are
+	// selected by, as in "tsc"
+	Short string
+	// Symbol tags the checker in compact status lines, as in "TSC"
+	Symbol string
+	// Aliases are further names the checker can be selected by, which are
+	// also the executables legacy results are recognized by
+	Aliases []string
+}
+
+// Defaults describe the command a checker runs unless configured otherwise
+type Defaults struct {
+	// Marker is the file that marks projects the command is configured for
+	// by default, as in "go.mod". Commands without one are part of every
+	// default configuration.
+	Marker  string
+	Command config.Command
+}
+
+// Checker is a kind of command kwatch knows how to run and read: a type
+// checker, linter, test runner or CI service. Checkers are registered
+// once and every frontend looks them up by command type.
+type Checker interface {
+	// Type is the command type, which is also the command's name in
+	// kwatch.yaml
+	Type() CommandType
+	Kind() CheckerKind
+	Labels() Labels
+	Defaults() Defaults
+	// WatchGlobs match the files, relative to the project, whose changes
+	// affect the checker's results
+	WatchGlobs() []string
+	// Parse fills in the issue count, diagnostics and test counts of a
+	// result from the command's output
+	Parse(parser *Parser, output string, result *CommandResult)
+}
+
+// TestCaseReporter is implemented by checkers whose parsed results list
+// individual tests
+type TestCaseReporter interface {
+	ReportsTestCases() bool
+}
+
+// registry holds the registered checkers in registration order
+var registry struct {
+	mutex    sync.RWMutex
+	checkers []Checker
+	byType   map[CommandType]Checker
+	byName   map[string]Checker
+}
+
+// Register adds a checker. Checkers are listed in the order they are
+// registered. Registering a command type or name twice
+// panics.
+func Register(checker Checker) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if registry.byType == nil {
+		registry.byType = make(map[CommandType]Checker)
+		registry.byName = make(map[string]Checker)
+	}
+
+	cmdType := checker.Type()
+	if _, exists := registry.byType[cmdType]; exists {
+		panic(fmt.Sprintf("runner: checker %s registered twice", cmdType))
+	}
+
+	labels := checker.Labels()
+	names := append([]string{string(cmdType), labels.Short}, labels.Aliases...)
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if name == "" {
+			continue
+		}
+		if existing, taken := registry.byName[name]; taken && existing != checker {
+			panic(fmt.Sprintf("runner: checker name %q registered twice", name))
+		}
+		registry.byName[name] = checker
+	}
+
+	registry.byType[cmdType] = checker
+	registry.checkers = append(registry.checkers, checker)
+}
+
+// Checkers returns the registered checkers in registration order
+func Checkers() []Checker {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return append([]Checker(nil), registry.checkers...)
+}
+
+// LookupChecker returns the checker registered for a command type
+func LookupChecker(cmdType CommandType) (Checker, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	checker, exists := registry.byType[cmdType]
+	return checker, exists
+}
+
+// FindChecker returns the checker a name refers to: its command type,
+// short name or one of its aliases, ignoring case
+func FindChecker(name string) (Checker, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	checker, exists := registry.byName[strings.ToLower(name)]
+	return checker, exists
+}
+
+// checkerRank returns the position a command type is listed at: the
+// registration order of its checker, after all checkers when there is none
+func checkerRank(cmdType CommandType) int {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	for i, checker := range registry.checkers {
+		if checker.Type() == cmdType {
+			return i
+		}
+	}
+	return len(registry.checkers)
+}
+
+// SortCommandTypes orders command types for display: registered checkers
+// first in registration order, then custom commands by name
+func SortCommandTypes(types []CommandType) {
+	sort.SliceStable(types, func(i, j int) bool {
+		ri, rj := checkerRank(types[i]), checkerRank(types[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return types[i] < types[j]
+	})
+}
+
+// Kind returns the kind of the command type's checker. Custom commands
+// without one are checks.
+func (t CommandType) Kind() CheckerKind {
+	if checker, exists := LookupChecker(t); exists {
+		return checker.Kind()
+	}
+	return KindCheck
+}
+
+// Name returns the human-readable name of the command type
+func (t CommandType) Name() string {
+	if checker, exists := LookupChecker(t); exists {
+		return checker.Labels().Name
+	}
+	return string(t)
+}
+
+// ShortName returns the name the command type is shown by in JSON status
+// output
+func (t CommandType) ShortName() string {
+	if checker, exists := LookupChecker(t); exists && checker.Labels().Short != "" {
+		return checker.Labels().Short
+	}
+	return string(t)
+}
+
+// Symbol returns the tag of the command type in compact status lines
+func (t CommandType) Symbol() string {
+	if checker, exists := LookupChecker(t); exists && checker.Labels().Symbol != "" {
+		return checker.Labels().Symbol
+	}
+	return strings.ToUpper(string(t))
+}
+
+// IsTestCommand reports whether a command type runs tests and reports test counts
+func IsTestCommand(cmdType CommandType) bool {
+	return cmdType.Kind() == KindTest
+}
+
+// reportsTestCases reports whether the command type's checker lists
+// individual tests
+func (t CommandType) reportsTestCases() bool {
+	checker, exists := LookupChecker(t)
+	if !exists {
+		return false
+	}
+	reporter, ok := checker.(TestCaseReporter)
+	return ok && reporter.ReportsTestCases()
+}
+
+// Watches reports whether a change to a file, relative to the project,
+// affects the checker's results
+func Watches(checker Checker, file string) bool {
+	return matchesAnyGlob(checker.WatchGlobs(), filepath.ToSlash(file))
+}
+
+// IsWatchedFile reports whether a change to a file, relative to the
+// project, affects any registered checker
+func IsWatchedFile(file string) bool {
+	for _, checker := range Checkers() {
+		if Watches(checker, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// getCommandType determines the command type of a legacy result from its
+// executable, falling back to the command itself
+func getCommandType(command string) CommandType {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return CommandType(command)
+	}
+	if checker, exists := FindChecker(filepath.Base(fields[0])); exists {
+		return checker.Type()
+	}
+	return CommandType(command)
+}
+
+// parseOutput fills in a result from the command's output with the
+// checker of its type, or generically for custom commands
+func (r *Runner) parseOutput(cmdType CommandType, output string, result *CommandResult) {
+	if checker, exists := LookupChecker(cmdType); exists {
+		checker.Parse(r.parser, output, result)
+		return
+	}
+	parseGeneric(r.parser, output, result)
+}
+
+// CommandNames returns the names of the configured commands in display
+// order, with checkers by their short names
+func (r *Runner) CommandNames() []string {
+	var types []CommandType
+	for cmdType := range r.getDefaultCommands() {
+		types = append(types, cmdType)
+	}
+	SortCommandTypes(types)
+
+	names := make([]string, 0, len(types))
+	for _, cmdType := range types {
+		names = append(names, cmdType.ShortName())
+	}
+	return names
+}
+
+// ResolveCommand returns the command a name refers to: the configured
+// command of the checker or custom command it names, or the checker's
+// default command when it is not configured
+func (r *Runner) ResolveCommand(name string) (Command, bool) {
+	cmdType := CommandType(name)
+	checker, known := FindChecker(name)
+	if known {
+		cmdType = checker.Type()
+	}
+
+	if command, exists := r.ConfiguredCommand(cmdType); exists {
+		return command, true
+	}
+	if !known || checker.Defaults().Command.Command == "" {
+		return Command{}, false
+	}
+
+	defaults := checker.Defaults().Command
+	timeout := 30 * time.Second
+	if r.kwatchConfig != nil {
+		timeout = r.kwatchConfig.GetTimeout(string(cmdType))
+	}
+	if duration, err := time.ParseDuration(defaults.Timeout); err == nil {
+		timeout = duration
+	}
+	return commandFromConfig(cmdType, defaults, timeout), true
+}
@@ -0,0 +1,263 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Coverage report formats
+const (
+	CoverageIstanbul = "istanbul"
+	CoverageLcov     = "lcov"
+	CoverageGo       = "go"
+)
+
+// CoverageMetric counts covered items of one kind: lines, branches or functions
+type CoverageMetric struct {
+	Covered int     `json:"covered"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"pct"`
+}
+
+// Coverage is the test coverage a run reported
+type Coverage struct {
+	Lines     CoverageMetric `json:"lines"`
+	Branches  CoverageMetric `json:"branches"`
+	Functions CoverageMetric `json:"functions"`
+	// Delta is the change since the previous run with coverage, in
+	// percentage points
+	Delta *CoverageDelta `json:"delta,omitempty"`
+}
+
+// CoverageDelta is the change of each coverage percentage between runs
+type CoverageDelta struct {
+	Lines     float64 `json:"lines"`
+	Branches  float64 `json:"branches"`
+	Functions float64 `json:"functions"`
+}
+
+// CoverageSettings tells the runner where a command writes coverage and
+// the minimum percentages it must reach
+type CoverageSettings struct {
+	Report string `json:"report,omitempty"`
+	// Format is istanbul, lcov or go; empty detects it from the content
+	Format       string  `json:"format,omitempty"`
+	MinLines     float64 `json:"min_lines,omitempty"`
+	MinBranches  float64 `json:"min_branches,omitempty"`
+	MinFunctions float64 `json:"min_functions,omitempty"`
+}
+
+// hasThresholds reports whether any minimum is set
+func (s CoverageSettings) hasThresholds() bool {
+	return s.MinLines > 0 || s.MinBranches > 0 || s.MinFunctions > 0
+}
+
+// newCoverageMetric builds a metric, computing its percentage
+func newCoverageMetric(covered, total int) CoverageMetric {
+	metric := CoverageMetric{Covered: covered, Total: total}
+	if total > 0 {
+		metric.Percent = float64(covered) * 100 / float64(total)
+	}
+	return metric
+}
+
+// Reported reports whether the coverage report included this metric
+func (m CoverageMetric) Reported() bool {
+	return m.Total > 0
+}
+
+// ParseCoverageReport parses a coverage report. An empty format detects
+// it: Go coverprofiles start with "mode:", istanbul summaries are JSON and
+// anything else is read as lcov.
+func ParseCoverageReport(data []byte, format string) (*Coverage, error) {
+	if format == "" {
+		trimmed := bytes.TrimSpace(data)
+		switch {
+		case bytes.HasPrefix(trimmed, []byte("mode:")):
+			format = CoverageGo
+		case bytes.HasPrefix(trimmed, []byte("{")):
+			format = CoverageIstanbul
+		default:
+			format = CoverageLcov
+		}
+	}
+
+	switch format {
+	case CoverageIstanbul:
+		return parseIstanbulSummary(data)
+	case CoverageLcov:
+		return parseLcov(data), nil
+	case CoverageGo:
+		return parseCoverprofile(data), nil
+	default:
+		return nil, fmt.Errorf("unknown coverage format %q", format)
+	}
+}
+
+// parseIstanbulSummary reads the totals of an istanbul coverage-summary.json
+func parseIstanbulSummary(data []byte) (*Coverage, error) {
+	type metric struct {
+		Total   int `json:"total"`
+		Covered int `json:"covered"`
+	}
+	var summary struct {
+		Total struct {
+			Lines     metric `json:"lines"`
+			Branches  metric `json:"branches"`
+			Functions metric `json:"functions"`
+		} `json:"total"`
+	}
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse istanbul coverage summary: %w", err)
+	}
+
+	total := summary.Total
+	return &Coverage{
+		Lines:     newCoverageMetric(total.Lines.Covered, total.Lines.Total),
+		Branches:  newCoverageMetric(total.Branches.Covered, total.Branches.Total),
+		Functions: newCoverageMetric(total.Functions.Covered, total.Functions.Total),
+	}, nil
+}
+
+// parseLcov sums the line, branch and function counts of every file in an
+// lcov tracefile
+func parseLcov(data []byte) *Coverage {
+	counts := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !found {
+			continue
+		}
+		switch key {
+		case "LF", "LH", "BRF", "BRH", "FNF", "FNH":
+			count, _ := strconv.Atoi(value)
+			counts[key] += count
+		}
+	}
+
+	return &Coverage{
+		Lines:     newCoverageMetric(counts["LH"], counts["LF"]),
+		Branches:  newCoverageMetric(counts["BRH"], counts["BRF"]),
+		Functions: newCoverageMetric(counts["FNH"], counts["FNF"]),
+	}
+}
+
+// parseCoverprofile reads a Go coverprofile. Go measures statements, which
+// are reported as lines. Blocks listed more than once, as happens with
+// -coverpkg, count once and are covered if any listing covers them.
+func parseCoverprofile(data []byte) *Coverage {
+	type block struct {
+		statements int
+		covered    bool
+	}
+	blocks := make(map[string]block)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		// file.go:10.2,12.3 2 1
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		statements, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+
+		existing := blocks[fields[0]]
+		blocks[fields[0]] = block{statements: statements, covered: existing.covered || count > 0}
+	}
+
+	covered, total := 0, 0
+	for _, b := range blocks {
+		total += b.statements
+		if b.covered {
+			covered += b.statements
+		}
+	}
+	return &Coverage{Lines: newCoverageMetric(covered, total)}
+}
+
+// coverageReportPath returns where the command's coverage report is written
+func (r *Runner) coverageReportPath(settings CoverageSettings) string {
+	if filepath.IsAbs(settings.Report) {
+		return settings.Report
+	}
+	return filepath.Join(r.config.WorkingDir, settings.Report)
+}
+
+// readCoverage parses the command's coverage report if the run wrote it.
+// It returns nil when the report is missing or unchanged since the snapshot.
+func (r *Runner) readCoverage(settings CoverageSettings, before reportSnapshot) (*Coverage, error) {
+	path := r.coverageReportPath(settings)
+	if !before.written(path) {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read coverage report: %w", err)
+	}
+	return ParseCoverageReport(data, settings.Format)
+}
+
+// checkCoverage returns why coverage is below the command's minimums, or
+// an empty string if it meets them
+func checkCoverage(settings CoverageSettings, coverage *Coverage) string {
+	if coverage == nil {
+		if settings.hasThresholds() {
+			return fmt.Sprintf("coverage report %s was not written", settings.Report)
+		}
+		return ""
+	}
+
+	checks := []struct {
+		name    string
+		metric  CoverageMetric
+		minimum float64
+	}{
+		{"line", coverage.Lines, settings.MinLines},
+		{"branch", coverage.Branches, settings.MinBranches},
+		{"function", coverage.Functions, settings.MinFunctions},
+	}
+	for _, check := range checks {
+		if check.minimum > 0 && check.metric.Reported() && check.metric.Percent < check.minimum {
+			return fmt.Sprintf("%s coverage %.1f%% is below the minimum of %.1f%%", check.name, check.metric.Percent, check.minimum)
+		}
+	}
+	return ""
+}
+
+// coverageDelta compares coverage with the previous run's. Metrics missing
+// from either run have no delta.
+func coverageDelta(current, previous *Coverage) *CoverageDelta {
+	delta := func(now, before CoverageMetric) float64 {
+		if !now.Reported() || !before.Reported() {
+			return 0
+		}
+		return now.Percent - before.Percent
+	}
+	return &CoverageDelta{
+		Lines:     delta(current.Lines, previous.Lines),
+		Branches:  delta(current.Branches, previous.Branches),
+		Functions: delta(current.Functions, previous.Functions),
+	}
+}
@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"regexp"
+)
+
+// Severity represents how serious a diagnostic is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Diagnostic represents a single issue reported by a tool
+type Diagnostic struct {
+	File     string   `json:"file,omitempty"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Rule     string   `json:"rule,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Tool     string   `json:"tool,omitempty"`
+	// Fixable marks problems the tool can fix automatically
+	Fixable bool `json:"fixable,omitempty"`
+}
+
+// ansiPattern matches terminal color escape sequences
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// stripANSI removes terminal color escape sequences from output
+func stripANSI(output string) string {
+	return ansiPattern.ReplaceAllString(output, "")
+}
+
+// CountDiagnosticFiles returns the number of distinct files with diagnostics
+func CountDiagnosticFiles(diagnostics []Diagnostic) int {
+	files := make(map[string]bool)
+	for _, d := range diagnostics {
+		if d.File != "" {
+			files[d.File] = true
+		}
+	}
+	return len(files)
+}
+
+// CountDiagnostics returns the number of error and warning diagnostics
+func CountDiagnostics(diagnostics []Diagnostic) (errors int, warnings int) {
+	for _, d := range diagnostics {
+		switch d.Severity {
+		case SeverityError:
+			errors++
+		case SeverityWarning:
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+// CountFixable returns the number of diagnostics the tool can fix automatically
+func CountFixable(diagnostics []Diagnostic) int {
+	fixable := 0
+	for _, d := range diagnostics {
+		if d.Fixable {
+			fixable++
+		}
+	}
+	return fixable
+}
@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventKind identifies what an Event reports
+type EventKind string
+
+const (
+	// EventStateChange reports a command whose status differs from its
+	// previous run, such as a passing command starting to fail
+	EventStateChange EventKind = "state_change"
+	// EventRegression reports a command whose duration or issue count
+	// rose above its baseline
+	EventRegression EventKind = "regression"
+)
+
+// Event is an alert about a command's results
+type Event struct {
+	Kind    EventKind   `json:"kind"`
+	Command CommandType `json:"command"`
+	// Status and Previous are the statuses of a state change
+	Status   ResultStatus `json:"status,omitempty"`
+	Previous ResultStatus `json:"previous,omitempty"`
+	// Regression describes a regression event
+	Regression *Regression `json:"regression,omitempty"`
+	Message    string      `json:"message"`
+	Timestamp  time.Time   `json:"timestamp"`
+}
+
+// EventHandler receives events as results are recorded. Handlers are
+// called synchronously from the finishing command and should return
+// quickly.
+type EventHandler func(Event)
+
+// SubscribeEvents registers a handler that receives the state changes and
+// regressions of every command run by this runner. The returned function
+// removes the handler.
+func (r *Runner) SubscribeEvents(handler EventHandler) (unsubscribe func()) {
+	return r.events.add(handler)
+}
+
+// ResultHandler receives results as they are recorded. Handlers are called
+// synchronously from the finishing command and should return quickly.
+type ResultHandler func(CommandResult)
+
+// SubscribeResults registers a handler that receives every result this
+// runner records, including skipped commands, but not superseded runs.
+// The returned function removes the handler.
+func (r *Runner) SubscribeResults(handler ResultHandler) (unsubscribe func()) {
+	return r.results.add(handler)
+}
+
+// addResult adds a result to the history, passes it to result subscribers
+// and tells event subscribers whether it changed the command's state or
+// started a regression
+func (r *Runner) addResult(result CommandResult) {
+	defer r.results.publish(result)
+	r.totals.add(result)
+
+	// Comparing with earlier runs reads the history, so only do it when
+	// someone is listening
+	if r.events.empty() {
+		r.history.Add(result)
+		return
+	}
+
+	cmdType := result.CommandType()
+	previous, runs := r.history.previousResults(cmdType, r.trends)
+	r.history.Add(result)
+
+	if previous != nil && previous.State() != result.State() {
+		r.events.publish(Event{
+			Kind:      EventStateChange,
+			Command:   cmdType,
+			Status:    result.State(),
+			Previous:  previous.State(),
+			Message:   fmt.Sprintf("%s %s → %s", cmdType, previous.State(), result.State()),
+			Timestamp: result.Timestamp,
+		})
+	}
+
+	// Only alert when a regression starts, not on every run while it lasts
+	if !countsForTrend(result) {
+		return
+	}
+	previousTrend := r.trends.analyze(cmdType, runs)
+	trend := r.trends.analyze(cmdType, append(runs, result))
+	for _, regression := range trend.Regressions {
+		if previousTrend.Regressed(regression.Metric) {
+			continue
+		}
+		regression := regression
+		r.events.publish(Event{
+			Kind:       EventRegression,
+			Command:    cmdType,
+			Regression: &regression,
+			Message:    regression.String(),
+			Timestamp:  result.Timestamp,
+		})
+	}
+}
@@ -0,0 +1,20 @@
+//go:build linux
+
+package runner
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// waitUnreaped blocks until a child process exits but leaves it to be
+// reaped, so its process ID, and the ID of the group it leads, cannot be
+// reused until then. It reports whether the wait succeeded.
+func waitUnreaped(pid int) bool {
+	var info unix.Siginfo
+	for {
+		err := unix.Waitid(unix.P_PID, pid, &info, unix.WEXITED|unix.WNOWAIT, nil)
+		if err != unix.EINTR {
+			return err == nil
+		}
+	}
+}
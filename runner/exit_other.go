@@ -0,0 +1,9 @@
+//go:build !linux
+
+package runner
+
+// waitUnreaped would wait for a child process to exit without reaping it;
+// it is only supported on Linux
+func waitUnreaped(pid int) bool {
+	return false
+}
@@ -0,0 +1,185 @@
+package runner
+
+import (
+	"sort"
+	"time"
+
+	"kwatch/config"
+)
+
+// DefaultFlakyMinScore is the flakiness score at which a test counts as
+// known-flaky when the config does not set one
+const DefaultFlakyMinScore = 0.1
+
+// FlakyPolicy decides how failures of known-flaky tests are reported
+type FlakyPolicy struct {
+	// Unstable reports runs whose only failures are known-flaky tests as
+	// unstable instead of failed
+	Unstable bool
+	// MinScore is the score at which a test counts as known-flaky
+	MinScore float64
+}
+
+// FlakyPolicyFromConfig builds a flaky test policy from the kwatch configuration
+func FlakyPolicyFromConfig(kwatchConfig *config.Config) FlakyPolicy {
+	return FlakyPolicy{
+		Unstable: kwatchConfig.Flaky.Unstable,
+		MinScore: kwatchConfig.FlakyMinScore(DefaultFlakyMinScore),
+	}
+}
+
+// FlakyTest describes a test that both passed and failed on the same
+// inputs. Only runs on working trees the test ran on more than once are
+// counted, since a change of outcome between different trees may be
+// caused by the change itself.
+type FlakyTest struct {
+	Command CommandType `json:"command"`
+	Name    string      `json:"name"`
+	File    string      `json:"file,omitempty"`
+	// Runs and Failures count the runs on repeated trees
+	Runs     int `json:"runs"`
+	Failures int `json:"failures"`
+	// Flips counts outcome changes between consecutive runs on one tree
+	Flips int `json:"flips"`
+	// Trees counts the trees on which the test both passed and failed
+	Trees int `json:"trees"`
+	// Score is the share of repeated runs whose outcome differed from the
+	// previous run on the same tree, from 0 (stable) to 1
+	Score    float64   `json:"score"`
+	LastFlip time.Time `json:"last_flip"`
+}
+
+// testKey identifies a test across runs
+type testKey struct {
+	command CommandType
+	name    string
+}
+
+// testOnTree tracks a test's outcomes on one working tree
+type testOnTree struct {
+	runs     int
+	failures int
+	last     TestCaseStatus
+	flipped  bool
+}
+
+// AnalyzeFlakiness scores every test in the results, which are expected
+// oldest first, and returns those that changed outcome on an unchanged
+// tree, most flaky first. Results without a tree hash are ignored, as are
+// cache hits, whose tests did not run again.
+func AnalyzeFlakiness(results []CommandResult) []FlakyTest {
+	trees := make(map[testKey]map[string]*testOnTree)
+	tests := make(map[testKey]*FlakyTest)
+
+	for _, result := range results {
+		if result.TreeHash == "" || len(result.TestCases) == 0 || result.Cached {
+			continue
+		}
+		for _, testCase := range result.TestCases {
+			if testCase.Status != TestCasePassed && testCase.Status != TestCaseFailed {
+				continue
+			}
+
+			key := testKey{command: result.CommandType(), name: testCaseName(testCase)}
+			if tests[key] == nil {
+				tests[key] = &FlakyTest{Command: key.command, Name: key.name, File: testCase.File}
+				trees[key] = make(map[string]*testOnTree)
+			}
+			test := tests[key]
+
+			tree := trees[key][result.TreeHash]
+			if tree == nil {
+				tree = &testOnTree{}
+				trees[key][result.TreeHash] = tree
+			}
+			if tree.runs > 0 && testCase.Status != tree.last {
+				test.Flips++
+				test.LastFlip = result.Timestamp
+				if !tree.flipped {
+					tree.flipped = true
+					test.Trees++
+				}
+			}
+			tree.runs++
+			tree.last = testCase.Status
+			if testCase.Status == TestCaseFailed {
+				tree.failures++
+			}
+		}
+	}
+
+	var flaky []FlakyTest
+	for key, test := range tests {
+		if test.Flips == 0 {
+			continue
+		}
+
+		repeats := 0
+		for _, tree := range trees[key] {
+			if tree.runs > 1 {
+				test.Runs += tree.runs
+				test.Failures += tree.failures
+				repeats += tree.runs - 1
+			}
+		}
+		test.Score = float64(test.Flips) / float64(repeats)
+		flaky = append(flaky, *test)
+	}
+
+	sort.Slice(flaky, func(i, j int) bool {
+		if flaky[i].Score != flaky[j].Score {
+			return flaky[i].Score > flaky[j].Score
+		}
+		if flaky[i].Flips != flaky[j].Flips {
+			return flaky[i].Flips > flaky[j].Flips
+		}
+		return flaky[i].Name < flaky[j].Name
+	})
+	return flaky
+}
+
+// testCaseName returns the name a test is tracked under across runs
+func testCaseName(testCase TestCase) string {
+	if testCase.ClassName == "" {
+		return testCase.Name
+	}
+	return testCase.ClassName + "." + testCase.Name
+}
+
+// Flakiness scores the tests in the history; see AnalyzeFlakiness
+func (h *ResultHistory) Flakiness() []FlakyTest {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	// Only results of runs listing tests on a known tree are analyzed
+	var results []CommandResult
+	for _, cmdType := range h.commandTypes() {
+		results = append(results, h.commandResults(cmdType, 0, func(result CommandResult) bool {
+			return result.TreeHash != "" && len(result.TestCases) > 0 && !result.Cached
+		})...)
+	}
+	return AnalyzeFlakiness(results)
+}
+
+// onlyFlakyFailures reports whether every failed test of a result is a
+// known-flaky test scoring at least minScore
+func onlyFlakyFailures(result CommandResult, flaky []FlakyTest, minScore float64) bool {
+	known := make(map[string]bool, len(flaky))
+	for _, test := range flaky {
+		if test.Command == result.CommandType() && test.Score >= minScore {
+			known[test.Name] = true
+		}
+	}
+
+	failures := 0
+	for _, testCase := range result.TestCases {
+		if testCase.Status != TestCaseFailed {
+			continue
+		}
+		if !known[testCaseName(testCase)] {
+			return false
+		}
+		failures++
+	}
+	return failures > 0
+}
@@ -0,0 +1,126 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Machine-readable output formats a command can declare instead of relying
+// on the built-in heuristics for its type
+const (
+	// FormatESLintJSON is the output of eslint --format json
+	FormatESLintJSON = "eslint-json"
+	// FormatTSC is the output of tsc --pretty false
+	FormatTSC = "tsc"
+)
+
+// eslintFileResult is one file of eslint --format json output
+type eslintFileResult struct {
+	FilePath string          `json:"filePath"`
+	Messages []eslintMessage `json:"messages"`
+}
+
+// eslintMessage is one problem reported by ESLint
+type eslintMessage struct {
+	RuleID   string          `json:"ruleId"`
+	Severity int             `json:"severity"`
+	Message  string          `json:"message"`
+	Line     int             `json:"line"`
+	Column   int             `json:"column"`
+	Fix      json.RawMessage `json:"fix"`
+}
+
+// SetFormat makes the parser read a command's output in a machine-readable format
+func (p *Parser) SetFormat(cmdType CommandType, format string) {
+	if p.formats == nil {
+		p.formats = make(map[CommandType]string)
+	}
+	p.formats[cmdType] = format
+}
+
+// Format returns the declared output format of a command, if any
+func (p *Parser) Format(cmdType CommandType) string {
+	return p.formats[cmdType]
+}
+
+// ParseFormat parses output in a machine-readable format into diagnostics
+func (p *Parser) ParseFormat(format, output string) ([]Diagnostic, error) {
+	switch format {
+	case FormatESLintJSON:
+		return parseESLintJSON(output)
+	case FormatTSC:
+		return p.parseTSCOutput(output), nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// parseESLintJSON reads eslint --format json output. Anything printed
+// before the JSON report, such as npx notices on stderr, is skipped.
+func parseESLintJSON(output string) ([]Diagnostic, error) {
+	start := jsonArrayStart(output)
+	if start < 0 {
+		return nil, fmt.Errorf("no ESLint JSON report in output")
+	}
+
+	var files []eslintFileResult
+	if err := json.NewDecoder(strings.NewReader(output[start:])).Decode(&files); err != nil {
+		return nil, fmt.Errorf("failed to parse ESLint JSON report: %w", err)
+	}
+
+	var diagnostics []Diagnostic
+	for _, file := range files {
+		for _, message := range file.Messages {
+			severity := SeverityWarning
+			if message.Severity == 2 {
+				severity = SeverityError
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				File:     file.FilePath,
+				Line:     message.Line,
+				Column:   message.Column,
+				Rule:     message.RuleID,
+				Severity: severity,
+				Message:  message.Message,
+				Tool:     "eslint",
+				Fixable:  len(message.Fix) > 0 && string(message.Fix) != "null",
+			})
+		}
+	}
+	return diagnostics, nil
+}
+
+// jsonArrayStart returns the offset of the first line of output that
+// starts a JSON array, or -1 if there is none
+func jsonArrayStart(output string) int {
+	start := 0
+	for _, line := range strings.SplitAfter(output, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "[") {
+			return start + strings.Index(line, "[")
+		}
+		start += len(line)
+	}
+	return -1
+}
+
+// parseTSCOutput reads tsc --pretty false output. Each diagnostic is one
+// "file(line,col): error TS1234: message" line; indented lines that follow
+// continue its message.
+func (p *Parser) parseTSCOutput(output string) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, line := range strings.Split(stripANSI(output), "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if diagnostic, ok := p.parseTypeScriptLine(line); ok {
+			diagnostics = append(diagnostics, diagnostic)
+			continue
+		}
+
+		if strings.HasPrefix(line, "  ") && strings.TrimSpace(line) != "" && len(diagnostics) > 0 {
+			last := &diagnostics[len(diagnostics)-1]
+			last.Message += "\n" + strings.TrimSpace(line)
+		}
+	}
+	return diagnostics
+}
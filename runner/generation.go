@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"context"
+	"sync"
+)
+
+// runTracker numbers each run of a command type with an increasing
+// generation and keeps track of the runs still in flight, so a newer
+// trigger can cancel them and their late results can be discarded
+type runTracker struct {
+	mutex       sync.Mutex
+	generations map[CommandType]uint64
+	recorded    map[CommandType]uint64
+	superseded  map[CommandType]uint64
+	inflight    map[CommandType]map[uint64]context.CancelFunc
+}
+
+// newRunTracker creates an empty run tracker
+func newRunTracker() *runTracker {
+	return &runTracker{
+		generations: make(map[CommandType]uint64),
+		recorded:    make(map[CommandType]uint64),
+		superseded:  make(map[CommandType]uint64),
+		inflight:    make(map[CommandType]map[uint64]context.CancelFunc),
+	}
+}
+
+// begin starts a new generation for a command type. The returned context is
+// cancelled when the run is superseded; finish must be called when the run ends.
+func (t *runTracker) begin(ctx context.Context, cmdType CommandType) (runCtx context.Context, generation uint64, finish func()) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.generations[cmdType]++
+	generation = t.generations[cmdType]
+
+	runCtx, cancel := context.WithCancel(ctx)
+	if t.inflight[cmdType] == nil {
+		t.inflight[cmdType] = make(map[uint64]context.CancelFunc)
+	}
+	t.inflight[cmdType][generation] = cancel
+
+	finish = func() {
+		t.mutex.Lock()
+		delete(t.inflight[cmdType], generation)
+		t.mutex.Unlock()
+		cancel()
+	}
+
+	return runCtx, generation, finish
+}
+
+// supersede cancels every in-flight run of a command type. Their results
+// will be reported as superseded and kept out of history.
+func (t *runTracker) supersede(cmdType CommandType) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.superseded[cmdType] = t.generations[cmdType]
+	for _, cancel := range t.inflight[cmdType] {
+		cancel()
+	}
+}
+
+// record marks a generation's result as the latest one for its command type.
+// It returns false if the run was superseded or a newer result was already
+// recorded, in which case the result is stale and must be discarded.
+func (t *runTracker) record(cmdType CommandType, generation uint64) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if generation <= t.superseded[cmdType] || generation < t.recorded[cmdType] {
+		return false
+	}
+	t.recorded[cmdType] = generation
+	return true
+}
@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// runGit runs git in dir and returns its trimmed output
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GitState returns the commit checked out in dir and whether the working
+// tree has uncommitted changes, ignoring the .kwatch directory
+func GitState(dir string) (sha string, dirty bool, err error) {
+	sha, err = runGit(dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", false, err
+	}
+	status, err := runGit(dir, "status", "--porcelain", "--", ":/", ":(exclude).kwatch")
+	if err != nil {
+		return sha, false, err
+	}
+	return sha, status != "", nil
+}
+
+// WorkingTreeHash returns an identity of the working tree in dir,
+// including uncommitted and untracked files that are not ignored, so two
+// runs with the same hash saw the same inputs. Paths matching the exclude
+// globs (relative to dir) and the .kwatch directory are left out. A clean
+// tree is identified by the git tree hash of HEAD; changed files are hashed
+// without writing objects into the repository.
+func WorkingTreeHash(dir string, exclude []string) (string, error) {
+	root, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	// A repository without commits has no tree yet
+	tree, _ := runGit(dir, "rev-parse", "--verify", "--quiet", "HEAD^{tree}")
+
+	args := []string{"status", "--porcelain", "-z", "--untracked-files=all", "--no-renames", "--", ":/", ":(exclude).kwatch"}
+	for _, pattern := range exclude {
+		args = append(args, ":(exclude,glob)"+pattern)
+	}
+	status, err := gitOutput(dir, "", args...)
+	if err != nil {
+		return "", err
+	}
+
+	// Entries are "XY path", with paths relative to the repository root
+	var changed, hashed []string
+	states := make(map[string]string)
+	for _, entry := range strings.Split(status, "\x00") {
+		if len(entry) < 4 {
+			continue
+		}
+		path := entry[3:]
+		changed = append(changed, path)
+		info, err := os.Lstat(filepath.Join(root, path))
+		switch {
+		case err != nil:
+			states[path] = "deleted"
+		case !info.Mode().IsRegular():
+			// Submodules and symlinks are identified by their status
+			states[path] = entry[:2]
+		default:
+			hashed = append(hashed, path)
+		}
+	}
+	if len(changed) == 0 {
+		return tree, nil
+	}
+
+	if len(hashed) > 0 {
+		var paths strings.Builder
+		for _, path := range hashed {
+			paths.WriteString(filepath.Join(root, path) + "\n")
+		}
+		blobs, err := gitOutput(dir, paths.String(), "hash-object", "--stdin-paths")
+		if err != nil {
+			return "", err
+		}
+		for i, blob := range strings.Fields(blobs) {
+			if i < len(hashed) {
+				states[hashed[i]] = blob
+			}
+		}
+	}
+
+	sort.Strings(changed)
+	hash := sha256.New()
+	fmt.Fprintf(hash, "tree %s\n", tree)
+	for _, path := range changed {
+		fmt.Fprintf(hash, "%s\x00%s\n", path, states[path])
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// gitOutput runs git in dir with the given input and returns its output
+// unchanged
+func gitOutput(dir, input string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdin = strings.NewReader(input)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(output), nil
+}
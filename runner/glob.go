@@ -0,0 +1,99 @@
+package runner
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// skippedInputDirs are never searched for input files
+var skippedInputDirs = map[string]bool{
+	".git":         true,
+	".kwatch":      true,
+	"node_modules": true,
+}
+
+// MatchGlob reports whether a slash-separated relative path matches a glob
+// pattern. Patterns use path.Match syntax, plus "**" to match any number
+// of directories.
+func MatchGlob(pattern, name string) bool {
+	return matchGlobParts(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchesAnyGlob reports whether a relative path matches any of the patterns
+func matchesAnyGlob(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if MatchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlobParts matches pattern segments against path segments
+func matchGlobParts(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// "**" swallows zero or more segments
+			for i := 0; i <= len(name); i++ {
+				if matchGlobParts(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if matched, err := path.Match(pattern[0], name[0]); err != nil || !matched {
+			return false
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+	return len(name) == 0
+}
+
+// ExpandGlobs returns the files under root matching any of the patterns,
+// as sorted slash-separated paths relative to root. Version control,
+// kwatch and node_modules directories are not searched. Files and
+// directories removed while searching are skipped.
+func ExpandGlobs(root string, patterns []string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if file != root && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if entry.IsDir() {
+			if file != root && skippedInputDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if matchesAnyGlob(patterns, rel) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
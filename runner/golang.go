@@ -0,0 +1,214 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TestPackage is the outcome of one package in a go test run
+type TestPackage struct {
+	Name     string         `json:"name"`
+	Status   TestCaseStatus `json:"status"`
+	Duration time.Duration  `json:"duration"`
+	// Output is kept for packages that failed, e.g. to build
+	Output string `json:"output,omitempty"`
+}
+
+// goTestEvent is one line of go test -json output
+type goTestEvent struct {
+	Action  string  `json:"Action"`
+	Package string  `json:"Package"`
+	Test    string  `json:"Test"`
+	Elapsed float64 `json:"Elapsed"`
+	Output  string  `json:"Output"`
+}
+
+// staticcheckProblem is one line of staticcheck -f json output
+type staticcheckProblem struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Location struct {
+		File   string `json:"file"`
+		Line   int    `json:"line"`
+		Column int    `json:"column"`
+	} `json:"location"`
+	Message string `json:"message"`
+}
+
+// ParseGoTestOutput parses the event stream of go test -json into per-test
+// and per-package results. Lines that are not events, such as compiler
+// errors printed by older Go versions, are parsed as Go diagnostics.
+func (p *Parser) ParseGoTestOutput(output string) TestReport {
+	var report TestReport
+	testOutput := make(map[string]*strings.Builder)
+	var buildOutput strings.Builder
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		var event goTestEvent
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &event) != nil {
+			buildOutput.WriteString(line + "\n")
+			continue
+		}
+
+		key := event.Package + " " + event.Test
+		switch event.Action {
+		case "output":
+			if testOutput[key] == nil {
+				testOutput[key] = &strings.Builder{}
+			}
+			testOutput[key].WriteString(event.Output)
+		case "build-output":
+			buildOutput.WriteString(event.Output)
+		case "pass", "fail", "skip":
+			status := goTestStatus(event.Action)
+			duration := time.Duration(event.Elapsed * float64(time.Second))
+			if event.Test == "" {
+				pkg := TestPackage{Name: event.Package, Status: status, Duration: duration}
+				if status == TestCaseFailed && testOutput[key] != nil {
+					pkg.Output = strings.TrimSpace(testOutput[key].String())
+				}
+				report.Packages = append(report.Packages, pkg)
+				continue
+			}
+
+			testCase := TestCase{
+				Name:      event.Test,
+				ClassName: event.Package,
+				Status:    status,
+				Duration:  duration,
+			}
+			if status != TestCasePassed && testOutput[key] != nil {
+				testCase.Message = goTestMessage(testOutput[key].String())
+			}
+			report.Cases = append(report.Cases, testCase)
+		}
+	}
+
+	report.Cases = leafTestCases(report.Cases)
+	report.Diagnostics = p.ParseGoOutput(buildOutput.String(), "go test")
+	return report
+}
+
+// leafTestCases drops the tests that have subtests, which go test reports
+// alongside them, so each test is counted once. A parent that failed while
+// none of its subtests did is kept, as the failure is its own.
+func leafTestCases(cases []TestCase) []TestCase {
+	type parent struct {
+		childFailed bool
+	}
+	parents := make(map[string]*parent)
+	for _, testCase := range cases {
+		name := testCase.Name
+		for i := strings.LastIndex(name, "/"); i > 0; i = strings.LastIndex(name, "/") {
+			name = name[:i]
+			key := testCase.ClassName + " " + name
+			if parents[key] == nil {
+				parents[key] = &parent{}
+			}
+			if testCase.Status == TestCaseFailed {
+				parents[key].childFailed = true
+			}
+		}
+	}
+
+	leaves := make([]TestCase, 0, len(cases))
+	for _, testCase := range cases {
+		if p := parents[testCase.ClassName+" "+testCase.Name]; p != nil {
+			if testCase.Status != TestCaseFailed || p.childFailed {
+				continue
+			}
+		}
+		leaves = append(leaves, testCase)
+	}
+	return leaves
+}
+
+// goTestStatus maps a go test -json action to a test status
+func goTestStatus(action string) TestCaseStatus {
+	switch action {
+	case "pass":
+		return TestCasePassed
+	case "skip":
+		return TestCaseSkipped
+	default:
+		return TestCaseFailed
+	}
+}
+
+// goTestMessage strips the "=== RUN" and "--- FAIL" framing from a test's output
+func goTestMessage(output string) string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "=== ") || strings.HasPrefix(trimmed, "--- ") {
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseGoOutput parses the "file.go:line:col: message" diagnostics printed
+// by go build and go vet. Indented lines continue the previous message.
+func (p *Parser) ParseGoOutput(output, tool string) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, line := range strings.Split(stripANSI(output), "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if matches := p.goDiagnosticPattern.FindStringSubmatch(line); matches != nil {
+			lineNum, _ := strconv.Atoi(matches[2])
+			column, _ := strconv.Atoi(matches[3])
+			diagnostics = append(diagnostics, Diagnostic{
+				File:     matches[1],
+				Line:     lineNum,
+				Column:   column,
+				Severity: SeverityError,
+				Message:  strings.TrimSpace(matches[4]),
+				Tool:     tool,
+			})
+			continue
+		}
+
+		if strings.HasPrefix(line, "\t") && strings.TrimSpace(line) != "" && len(diagnostics) > 0 {
+			last := &diagnostics[len(diagnostics)-1]
+			last.Message += "\n" + strings.TrimSpace(line)
+		}
+	}
+	return diagnostics
+}
+
+// ParseStaticcheckOutput parses staticcheck -f json output, one problem per line
+func (p *Parser) ParseStaticcheckOutput(output string) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, line := range strings.Split(output, "\n") {
+		var problem staticcheckProblem
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &problem) != nil {
+			continue
+		}
+
+		severity := SeverityError
+		switch problem.Severity {
+		case "ignored":
+			continue
+		case "warning":
+			severity = SeverityWarning
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     problem.Location.File,
+			Line:     problem.Location.Line,
+			Column:   problem.Location.Column,
+			Rule:     problem.Code,
+			Severity: severity,
+			Message:  problem.Message,
+			Tool:     "staticcheck",
+		})
+	}
+	return diagnostics
+}
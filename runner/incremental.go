@@ -0,0 +1,153 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ChangedFilesPlaceholder is replaced by the changed files in command arguments
+const ChangedFilesPlaceholder = "{{changedFiles}}"
+
+// baselines keeps the last full result of each command, so incremental
+// results can be merged into it
+type baselines struct {
+	results map[CommandType]CommandResult
+	mutex   sync.Mutex
+}
+
+// Incremental reports whether the command runs on a set of changed files
+func (c Command) Incremental() bool {
+	return len(c.ChangedFiles) > 0
+}
+
+// supportsChangedFiles reports whether the command's arguments can take
+// the changed files
+func (c Command) supportsChangedFiles() bool {
+	if len(c.IncrementalArgs) > 0 {
+		return true
+	}
+	for _, arg := range c.Args {
+		if strings.Contains(arg, ChangedFilesPlaceholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// ForChangedFiles returns the command set up to check only the changed
+// files that match its IncrementalFiles globs. Commands that cannot take
+// changed files are returned unchanged for a full run. The second result
+// is false when the command supports incremental runs but none of the
+// files are relevant to it, so it does not need to run.
+func (c Command) ForChangedFiles(workingDir string, files []string) (Command, bool) {
+	if !c.supportsChangedFiles() {
+		return c, true
+	}
+
+	var relevant []string
+	for _, file := range files {
+		rel := file
+		if filepath.IsAbs(file) && workingDir != "" {
+			if r, err := filepath.Rel(workingDir, file); err == nil {
+				rel = r
+			}
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(rel, "../") {
+			continue
+		}
+		if len(c.IncrementalFiles) == 0 || matchesAnyGlob(c.IncrementalFiles, rel) {
+			relevant = append(relevant, rel)
+		}
+	}
+	if len(relevant) == 0 {
+		return c, false
+	}
+
+	c.ChangedFiles = relevant
+	return c, true
+}
+
+// resolvedArgs returns the arguments to run the command with, expanding
+// the changed files placeholder. Full runs drop the placeholder.
+func (c Command) resolvedArgs() []string {
+	template := c.Args
+	if c.Incremental() && len(c.IncrementalArgs) > 0 {
+		template = c.IncrementalArgs
+	}
+
+	args := make([]string, 0, len(template)+len(c.ChangedFiles))
+	for _, arg := range template {
+		switch {
+		case arg == ChangedFilesPlaceholder:
+			args = append(args, c.ChangedFiles...)
+		case strings.Contains(arg, ChangedFilesPlaceholder):
+			args = append(args, strings.ReplaceAll(arg, ChangedFilesPlaceholder, strings.Join(c.ChangedFiles, " ")))
+		default:
+			args = append(args, arg)
+		}
+	}
+	return args
+}
+
+// mergeIncremental folds an incremental result into the last full result
+// of the command: diagnostics of the re-checked files are replaced, those
+// of every other file are kept. Without a full result to merge into, the
+// incremental result is returned as is.
+func (r *Runner) mergeIncremental(command Command, result CommandResult) CommandResult {
+	r.baselines.mutex.Lock()
+	defer r.baselines.mutex.Unlock()
+
+	baseline, exists := r.baselines.results[command.Type]
+	if !exists {
+		return result
+	}
+
+	checked := make(map[string]bool, len(command.ChangedFiles))
+	for _, file := range command.ChangedFiles {
+		checked[r.absolutePath(file)] = true
+	}
+
+	var remaining []Diagnostic
+	for _, diagnostic := range baseline.Diagnostics {
+		if !checked[r.absolutePath(diagnostic.File)] {
+			remaining = append(remaining, diagnostic)
+		}
+	}
+
+	// Files that were not re-checked keep failing the command if they have
+	// errors, or warnings when the full run failed on warnings alone
+	errors, warnings := CountDiagnostics(remaining)
+	baselineErrors, _ := CountDiagnostics(baseline.Diagnostics)
+	warningsFail := !baseline.Passed && baselineErrors == 0
+	result.Passed = result.Passed && errors == 0 && !(warningsFail && warnings > 0)
+
+	result.setDiagnostics(append(remaining, result.Diagnostics...))
+	result.IssueCount = len(result.Diagnostics)
+	// Statuses such as unstable or terminated were decided by the run itself
+	if result.Status == StatusPassed || result.Status == StatusFailed {
+		result.Status = statusFromPassed(result.Passed)
+	}
+	return result
+}
+
+// updateBaseline records a result as the one later incremental results
+// are merged into
+func (r *Runner) updateBaseline(cmdType CommandType, result CommandResult) {
+	r.baselines.mutex.Lock()
+	defer r.baselines.mutex.Unlock()
+
+	if r.baselines.results == nil {
+		r.baselines.results = make(map[CommandType]CommandResult)
+	}
+	r.baselines.results[cmdType] = result
+}
+
+// absolutePath resolves a path reported by a tool against the working directory
+func (r *Runner) absolutePath(file string) string {
+	if file == "" || filepath.IsAbs(file) {
+		return filepath.Clean(file)
+	}
+	return filepath.Join(r.config.WorkingDir, file)
+}
@@ -0,0 +1,236 @@
+package runner
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TestCaseStatus describes how a single test ended
+type TestCaseStatus string
+
+const (
+	TestCasePassed  TestCaseStatus = "passed"
+	TestCaseFailed  TestCaseStatus = "failed"
+	TestCaseSkipped TestCaseStatus = "skipped"
+)
+
+// TestCase is one test read from a test report
+type TestCase struct {
+	Name      string         `json:"name"`
+	ClassName string         `json:"class_name,omitempty"`
+	File      string         `json:"file,omitempty"`
+	Status    TestCaseStatus `json:"status"`
+	Duration  time.Duration  `json:"duration"`
+	// Message describes why a failed or skipped test did not pass
+	Message string `json:"message,omitempty"`
+}
+
+// reportsTestCases reports whether the command's results can list
+// individual tests
+func (c Command) reportsTestCases() bool {
+	return c.Type.reportsTestCases() || len(c.Reports) > 0
+}
+
+// outputFiles returns globs of the report files the command writes, which
+// are not inputs of its next run
+func (c Command) outputFiles() []string {
+	files := append([]string(nil), c.Reports...)
+	if c.Coverage.Report != "" {
+		files = append(files, c.Coverage.Report)
+	}
+	return files
+}
+
+// TestReport holds the per-test results a test runner reported
+type TestReport struct {
+	Cases    []TestCase
+	Packages []TestPackage
+	// Diagnostics are compile errors of code that failed to build
+	Diagnostics []Diagnostic
+}
+
+// junitSuite is a <testsuite> element; suites may nest
+type junitSuite struct {
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+// junitCase is a <testcase> element
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+}
+
+// junitMessage is a <failure>, <error> or <skipped> element
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// text returns the message attribute, or the element body if there is none
+func (m *junitMessage) text() string {
+	if message := strings.TrimSpace(m.Message); message != "" {
+		return message
+	}
+	return strings.TrimSpace(m.Body)
+}
+
+// ParseJUnitReport reads the test cases of a JUnit XML report. Both a
+// <testsuites> root and a single <testsuite> root are accepted.
+func ParseJUnitReport(data []byte) ([]TestCase, error) {
+	var root junitSuite
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse JUnit report: %w", err)
+	}
+
+	var cases []TestCase
+	collectJUnitCases(root, &cases)
+	return cases, nil
+}
+
+// collectJUnitCases appends the test cases of a suite and its nested suites
+func collectJUnitCases(suite junitSuite, cases *[]TestCase) {
+	for _, c := range suite.Cases {
+		testCase := TestCase{
+			Name:      c.Name,
+			ClassName: c.ClassName,
+			File:      c.File,
+			Status:    TestCasePassed,
+			Duration:  parseJUnitTime(c.Time),
+		}
+		switch {
+		case c.Failure != nil:
+			testCase.Status = TestCaseFailed
+			testCase.Message = c.Failure.text()
+		case c.Error != nil:
+			testCase.Status = TestCaseFailed
+			testCase.Message = c.Error.text()
+		case c.Skipped != nil:
+			testCase.Status = TestCaseSkipped
+			testCase.Message = c.Skipped.text()
+		}
+		*cases = append(*cases, testCase)
+	}
+
+	for _, nested := range suite.Suites {
+		collectJUnitCases(nested, cases)
+	}
+}
+
+// parseJUnitTime parses a duration in seconds, as written by JUnit reporters
+func parseJUnitTime(value string) time.Duration {
+	seconds, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// reportStamp identifies one version of a report file
+type reportStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// reportSnapshot records the reports present before a run, so reports the
+// run did not rewrite are not read as its own
+type reportSnapshot map[string]reportStamp
+
+// snapshotReports records the test and coverage reports left over from
+// earlier runs of a command. It returns nil if the command reads no reports.
+func (r *Runner) snapshotReports(command Command) (reportSnapshot, error) {
+	if len(command.Reports) == 0 && command.Coverage.Report == "" {
+		return nil, nil
+	}
+
+	var paths []string
+	if len(command.Reports) > 0 {
+		files, err := ExpandGlobs(r.config.WorkingDir, command.Reports)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find reports: %w", err)
+		}
+		for _, file := range files {
+			paths = append(paths, filepath.Join(r.config.WorkingDir, filepath.FromSlash(file)))
+		}
+	}
+	if command.Coverage.Report != "" {
+		paths = append(paths, r.coverageReportPath(command.Coverage))
+	}
+
+	snapshot := reportSnapshot{}
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			snapshot[path] = reportStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return snapshot, nil
+}
+
+// written reports whether the file at path exists and was created or
+// rewritten since the snapshot was taken
+func (s reportSnapshot) written(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	before, existed := s[path]
+	return !existed || !info.ModTime().Equal(before.modTime) || info.Size() != before.size
+}
+
+// readReports parses the reports matching the command's globs that the
+// run wrote, leaving out those unchanged since the snapshot. It returns
+// false if no report was found.
+func (r *Runner) readReports(command Command, before reportSnapshot) ([]TestCase, bool, error) {
+	files, err := ExpandGlobs(r.config.WorkingDir, command.Reports)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find reports: %w", err)
+	}
+
+	var cases []TestCase
+	found := false
+	for _, file := range files {
+		path := filepath.Join(r.config.WorkingDir, filepath.FromSlash(file))
+		if !before.written(path) {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read report %s: %w", file, err)
+		}
+		reportCases, err := ParseJUnitReport(data)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", file, err)
+		}
+		cases = append(cases, reportCases...)
+		found = true
+	}
+
+	return cases, found, nil
+}
+
+// testResultFromCases counts the outcomes of test cases
+func testResultFromCases(cases []TestCase) TestResult {
+	result := TestResult{TotalTests: len(cases)}
+	for _, testCase := range cases {
+		switch testCase.Status {
+		case TestCaseFailed:
+			result.FailedTests++
+		case TestCaseSkipped:
+			result.SkippedTests++
+		default:
+			result.PassedTests++
+		}
+	}
+	result.Passed = result.FailedTests == 0
+	return result
+}
@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// ResourceLimits caps the resources a command may use. Zero values are
+// unlimited. Limits are only enforced on Linux.
+type ResourceLimits struct {
+	// Memory is in bytes
+	Memory int64 `json:"memory,omitempty"`
+	// CPUTime is the CPU time each process of the command may use
+	CPUTime   time.Duration `json:"cpu_time,omitempty"`
+	OpenFiles int           `json:"open_files,omitempty"`
+	Processes int           `json:"processes,omitempty"`
+}
+
+// Limits a command can exceed, as reported in CommandResult.LimitExceeded
+const (
+	LimitMemory    = "memory"
+	LimitCPUTime   = "cpu_time"
+	LimitOpenFiles = "open_files"
+	LimitProcesses = "processes"
+)
+
+// limitMessages recognize the errors processes report when an rlimit stops
+// them, for limits the kernel does not report directly
+var limitMessages = map[string]*regexp.Regexp{
+	LimitMemory:    regexp.MustCompile(`(?i)cannot allocate memory|out of memory|MemoryError|\bENOMEM\b|std::bad_alloc`),
+	LimitOpenFiles: regexp.MustCompile(`(?i)too many open files|\bEMFILE\b`),
+	LimitProcesses: regexp.MustCompile(`(?i)fork: (retry: )?resource temporarily unavailable|\bEAGAIN\b.*\bspawn\b`),
+}
+
+// IsZero reports whether no limit is set
+func (l ResourceLimits) IsZero() bool {
+	return l == ResourceLimits{}
+}
+
+// limitError describes an exceeded limit for CommandResult.Error
+func (l ResourceLimits) limitError(limit string) string {
+	switch limit {
+	case LimitMemory:
+		return fmt.Sprintf("command exceeded its memory limit of %s", FormatBytes(l.Memory))
+	case LimitCPUTime:
+		return fmt.Sprintf("command exceeded its CPU time limit of %s", l.CPUTime)
+	case LimitOpenFiles:
+		return fmt.Sprintf("command exceeded its limit of %d open files", l.OpenFiles)
+	case LimitProcesses:
+		return fmt.Sprintf("command exceeded its limit of %d processes", l.Processes)
+	default:
+		return "command exceeded a resource limit"
+	}
+}
+
+// exceededByOutput guesses which set limit stopped a failed command from
+// the errors in its output
+func (l ResourceLimits) exceededByOutput(output string) string {
+	checks := []struct {
+		limit string
+		set   bool
+	}{
+		{LimitMemory, l.Memory > 0},
+		{LimitOpenFiles, l.OpenFiles > 0},
+		{LimitProcesses, l.Processes > 0},
+	}
+	for _, check := range checks {
+		if check.set && limitMessages[check.limit].MatchString(output) {
+			return check.limit
+		}
+	}
+	return ""
+}
+
+// FormatBytes formats a byte count with a binary unit ("1.5GiB")
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
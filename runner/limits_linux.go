@@ -0,0 +1,358 @@
+//go:build linux
+
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// cgroupRoot is where the cgroup v2 hierarchy is mounted
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupSequence numbers the cgroups created by this process
+var cgroupSequence atomic.Uint64
+
+// rlimitHelper is the argument that makes kwatch set rlimits on itself
+// and then exec a command; see execWithRlimits
+const rlimitHelper = "__kwatch-rlimit"
+
+// Commands are started through kwatch itself when rlimits are set, so the
+// limits are in place before the command runs
+func init() {
+	if len(os.Args) > 1 && os.Args[1] == rlimitHelper {
+		execWithRlimits(os.Args[2:])
+	}
+}
+
+// limiter enforces a command's resource limits for one run. Memory and
+// process limits use a cgroup v2 group of the run's own when the hierarchy
+// delegates the controllers, so they cover every process the command
+// starts. Without one, memory falls back to an rlimit and a process limit
+// is an error. CPU time and open files are rlimits, set before the command
+// is executed and inherited by its children.
+type limiter struct {
+	limits   ResourceLimits
+	cgroup   string
+	cgroupFD *os.File
+	err      error
+}
+
+// newLimiter prepares the enforcement of limits, returning nil when no
+// limit is set
+func newLimiter(limits ResourceLimits) *limiter {
+	if limits.IsZero() {
+		return nil
+	}
+
+	l := &limiter{limits: limits}
+	if limits.Memory > 0 || limits.Processes > 0 {
+		dir, err := createRunCgroup(limits)
+		if err == nil {
+			var fd *os.File
+			if fd, err = os.Open(dir); err == nil {
+				l.cgroup = dir
+				l.cgroupFD = fd
+			} else {
+				os.Remove(dir)
+			}
+		}
+		// No rlimit limits only the command's processes
+		if err != nil && limits.Processes > 0 {
+			l.err = fmt.Errorf("limits.processes needs a cgroup v2 group for the run: %w", err)
+		}
+	}
+	return l
+}
+
+// prepare makes the command start inside the run's cgroup, with its
+// rlimits set before it is executed
+func (l *limiter) prepare(cmd *exec.Cmd) error {
+	if l == nil {
+		return nil
+	}
+	if l.err != nil {
+		return l.err
+	}
+
+	if l.cgroupFD != nil {
+		if cmd.SysProcAttr == nil {
+			cmd.SysProcAttr = &syscall.SysProcAttr{}
+		}
+		cmd.SysProcAttr.UseCgroupFD = true
+		cmd.SysProcAttr.CgroupFD = int(l.cgroupFD.Fd())
+	}
+
+	rlimits := l.rlimits()
+	if len(rlimits) == 0 || cmd.Err != nil {
+		return nil
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to set resource limits: %w", err)
+	}
+	args := append([]string{self, rlimitHelper}, rlimits...)
+	args = append(args, "--", cmd.Path)
+	cmd.Args = append(args, cmd.Args...)
+	cmd.Path = self
+	return nil
+}
+
+// rlimits returns the rlimits to set as resource=soft:hard arguments for
+// execWithRlimits
+func (l *limiter) rlimits() []string {
+	var rlimits []string
+	if l.limits.CPUTime > 0 {
+		// SIGXCPU at the limit, SIGKILL a second later if it is ignored
+		seconds := uint64(math.Ceil(l.limits.CPUTime.Seconds()))
+		rlimits = append(rlimits, fmt.Sprintf("%d=%d:%d", unix.RLIMIT_CPU, seconds, seconds+1))
+	}
+	if l.limits.OpenFiles > 0 {
+		rlimits = append(rlimits, fmt.Sprintf("%d=%d:%d", unix.RLIMIT_NOFILE, l.limits.OpenFiles, l.limits.OpenFiles))
+	}
+	if l.limits.Memory > 0 && l.cgroup == "" {
+		rlimits = append(rlimits, fmt.Sprintf("%d=%d:%d", unix.RLIMIT_DATA, l.limits.Memory, l.limits.Memory))
+	}
+	return rlimits
+}
+
+// execWithRlimits sets the rlimits given as resource=soft:hard arguments
+// up to "--", then executes the path and arguments that follow. It only
+// returns by exiting.
+func execWithRlimits(args []string) {
+	for len(args) > 0 && args[0] != "--" {
+		if err := setRlimit(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "kwatch: failed to set resource limit: %v\n", err)
+			os.Exit(126)
+		}
+		args = args[1:]
+	}
+	if len(args) < 3 {
+		fmt.Fprintln(os.Stderr, "kwatch: no command to run with resource limits")
+		os.Exit(126)
+	}
+
+	err := syscall.Exec(args[1], args[2:], os.Environ())
+	fmt.Fprintf(os.Stderr, "kwatch: failed to run %s: %v\n", args[2], err)
+	os.Exit(127)
+}
+
+// exceeded returns the limit that stopped the command, or an empty string
+func (l *limiter) exceeded(state *os.ProcessState, output string) string {
+	if l == nil {
+		return ""
+	}
+
+	if l.cgroup != "" {
+		if l.limits.Memory > 0 && cgroupEvents(l.cgroup, "memory.events")["oom_kill"] > 0 {
+			return LimitMemory
+		}
+		if l.limits.Processes > 0 && cgroupEvents(l.cgroup, "pids.events")["max"] > 0 {
+			return LimitProcesses
+		}
+	}
+
+	if l.limits.CPUTime > 0 && state != nil {
+		if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() && status.Signal() == syscall.SIGXCPU {
+			return LimitCPUTime
+		}
+		// Shells report a child killed by a signal as 128 plus its number
+		if state.ExitCode() == 128+int(syscall.SIGXCPU) {
+			return LimitCPUTime
+		}
+	}
+
+	if state != nil && state.Success() {
+		return ""
+	}
+	// The kernel reports exceeded rlimits only to the process that hit them
+	guess := l.limits
+	if l.cgroup != "" {
+		guess.Memory, guess.Processes = 0, 0
+	}
+	return guess.exceededByOutput(output)
+}
+
+// groupUsage replaces the usage measured for the command's process with
+// the totals of the run's cgroup, which include every process the command
+// started. Values the kernel does not report are left unchanged.
+func (l *limiter) groupUsage(usage ResourceUsage) ResourceUsage {
+	if l == nil || l.cgroup == "" {
+		return usage
+	}
+
+	stat := cgroupEvents(l.cgroup, "cpu.stat")
+	if userUsec, found := stat["user_usec"]; found {
+		usage.UserCPU = time.Duration(userUsec) * time.Microsecond
+		usage.SystemCPU = time.Duration(stat["system_usec"]) * time.Microsecond
+	}
+	// memory.peak needs Linux 5.19
+	if data, err := os.ReadFile(filepath.Join(l.cgroup, "memory.peak")); err == nil {
+		if peak, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil {
+			usage.PeakRSS = peak
+		}
+	}
+	return usage
+}
+
+// release removes the run's cgroup, killing any process left in it
+func (l *limiter) release() {
+	if l == nil || l.cgroup == "" {
+		return
+	}
+
+	l.cgroupFD.Close()
+	os.WriteFile(filepath.Join(l.cgroup, "cgroup.kill"), []byte("1"), 0644)
+	for i := 0; i < 50; i++ {
+		if err := os.Remove(l.cgroup); err == nil || os.IsNotExist(err) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// createRunCgroup creates a cgroup for one run with the memory and process
+// limits set. A group with processes cannot delegate controllers to child
+// groups, so unless kwatch runs in the root group, the run's group is
+// created next to kwatch's own.
+func createRunCgroup(limits ResourceLimits) (string, error) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return "", fmt.Errorf("cgroup v2 is not mounted: %w", err)
+	}
+
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	var own string
+	for _, line := range strings.Split(string(data), "\n") {
+		if path, found := strings.CutPrefix(line, "0::"); found {
+			own = filepath.Join(cgroupRoot, path)
+		}
+	}
+	if own == "" {
+		return "", fmt.Errorf("process is not in a cgroup v2 group")
+	}
+
+	var controllers []string
+	if limits.Memory > 0 {
+		controllers = append(controllers, "memory")
+	}
+	if limits.Processes > 0 {
+		controllers = append(controllers, "pids")
+	}
+
+	parents := []string{own}
+	if own != cgroupRoot {
+		parents = append(parents, filepath.Dir(own))
+	}
+	for _, parent := range parents {
+		if !delegatesControllers(parent, controllers) {
+			continue
+		}
+
+		dir := filepath.Join(parent, fmt.Sprintf("kwatch-%d-%d", os.Getpid(), cgroupSequence.Add(1)))
+		if err := os.Mkdir(dir, 0755); err != nil {
+			continue
+		}
+		if err := writeCgroupLimits(dir, limits); err != nil {
+			os.Remove(dir)
+			continue
+		}
+		return dir, nil
+	}
+	return "", fmt.Errorf("no cgroup delegates the %s controllers", strings.Join(controllers, " and "))
+}
+
+// delegatesControllers reports whether child groups of dir have the controllers
+func delegatesControllers(dir string, controllers []string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, "cgroup.subtree_control"))
+	if err != nil {
+		return false
+	}
+	enabled := strings.Fields(string(data))
+	for _, controller := range controllers {
+		found := false
+		for _, name := range enabled {
+			if name == controller {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// writeCgroupLimits sets the memory and process limits of a cgroup
+func writeCgroupLimits(dir string, limits ResourceLimits) error {
+	if limits.Memory > 0 {
+		memory := []byte(strconv.FormatInt(limits.Memory, 10))
+		if err := os.WriteFile(filepath.Join(dir, "memory.max"), memory, 0644); err != nil {
+			return err
+		}
+		// Swap would let the command use more memory than the limit
+		os.WriteFile(filepath.Join(dir, "memory.swap.max"), []byte("0"), 0644)
+	}
+	if limits.Processes > 0 {
+		pids := []byte(strconv.Itoa(limits.Processes))
+		if err := os.WriteFile(filepath.Join(dir, "pids.max"), pids, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cgroupEvents reads the counters of a cgroup events or stat file
+func cgroupEvents(dir, name string) map[string]int64 {
+	events := make(map[string]int64)
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return events
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if count, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			events[fields[0]] = count
+		}
+	}
+	return events
+}
+
+// setRlimit sets one of kwatch's own rlimits from a resource=soft:hard
+// argument, keeping it within the current hard limit, which unprivileged
+// processes cannot raise
+func setRlimit(arg string) error {
+	var resource int
+	var soft, hard uint64
+	if _, err := fmt.Sscanf(arg, "%d=%d:%d", &resource, &soft, &hard); err != nil {
+		return fmt.Errorf("invalid limit %q", arg)
+	}
+
+	var current syscall.Rlimit
+	if err := syscall.Getrlimit(resource, &current); err != nil {
+		return err
+	}
+	// syscall.Setrlimit also keeps exec from restoring Go's default for
+	// open files
+	limit := syscall.Rlimit{Cur: min(soft, current.Max), Max: min(hard, current.Max)}
+	return syscall.Setrlimit(resource, &limit)
+}
@@ -0,0 +1,35 @@
+//go:build !linux
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+)
+
+// limiter would enforce a command's resource limits; they are only
+// supported on Linux
+type limiter struct{}
+
+// newLimiter returns nil, leaving commands unlimited
+func newLimiter(limits ResourceLimits) *limiter {
+	return nil
+}
+
+// prepare does nothing
+func (l *limiter) prepare(cmd *exec.Cmd) error {
+	return nil
+}
+
+// exceeded reports no limit
+func (l *limiter) exceeded(state *os.ProcessState, output string) string {
+	return ""
+}
+
+// groupUsage returns the usage unchanged
+func (l *limiter) groupUsage(usage ResourceUsage) ResourceUsage {
+	return usage
+}
+
+// release does nothing
+func (l *limiter) release() {}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package runner
+
+import "sync"
+
+// dirLocks serializes access to shared directories within this process.
+// Windows builds do not lock across processes.
+var dirLocks sync.Map
+
+// lockDir takes an exclusive lock on a directory. The returned function
+// releases it.
+func lockDir(dir string) (unlock func(), err error) {
+	lock, _ := dirLocks.LoadOrStore(dir, &sync.Mutex{})
+	mutex := lock.(*sync.Mutex)
+	mutex.Lock()
+	return mutex.Unlock, nil
+}
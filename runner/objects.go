@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// objectPath returns where an output with the given hash is stored
+func (s *HistoryStore) objectPath(hash string) string {
+	return filepath.Join(s.dir, "objects", hash[:2], hash)
+}
+
+// storeOutput writes a result's output to the object store, unless an
+// identical output is already there, and returns the result with the
+// output replaced by its hash
+func (s *HistoryStore) storeOutput(result CommandResult) (CommandResult, error) {
+	if result.Output == "" {
+		return result, nil
+	}
+
+	sum := sha256.Sum256([]byte(result.Output))
+	hash := hex.EncodeToString(sum[:])
+	path := s.objectPath(hash)
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return result, fmt.Errorf("failed to create output directory: %w", err)
+		}
+		if err := os.WriteFile(path+".tmp", []byte(result.Output), 0644); err != nil {
+			return result, fmt.Errorf("failed to write output: %w", err)
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return result, fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+
+	s.outputs[hash] = result.Output
+	result.Output = ""
+	result.OutputHash = hash
+	return result, nil
+}
+
+// attachOutput fills in the output of a stored result from the object store
+func (s *HistoryStore) attachOutput(result *CommandResult) {
+	if result.OutputHash == "" || result.Output != "" {
+		return
+	}
+
+	if output, exists := s.outputs[result.OutputHash]; exists {
+		result.Output = output
+		return
+	}
+
+	data, err := os.ReadFile(s.objectPath(result.OutputHash))
+	if err != nil {
+		// The output was pruned or lost; keep the rest of the result
+		return
+	}
+	s.outputs[result.OutputHash] = string(data)
+	result.Output = string(data)
+}
+
+// removeUnusedOutputs deletes stored outputs that none of the results
+// reference and returns how many were removed
+func (s *HistoryStore) removeUnusedOutputs(results []CommandResult) (int, error) {
+	referenced := make(map[string]bool, len(results))
+	for _, result := range results {
+		if result.OutputHash != "" {
+			referenced[result.OutputHash] = true
+		}
+	}
+
+	removed := 0
+	root := filepath.Join(s.dir, "objects")
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || referenced[entry.Name()] {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		delete(s.outputs, entry.Name())
+		removed++
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("failed to remove unused outputs: %w", err)
+	}
+	return removed, nil
+}
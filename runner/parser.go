@@ -9,12 +9,22 @@ import (
 // Parser handles parsing of command output to extract meaningful information
 type Parser struct {
 	// Regex patterns for different tools
-	tscErrorPattern    *regexp.Regexp
-	eslintPattern      *regexp.Regexp
-	testFailPattern    *regexp.Regexp
-	testPassPattern    *regexp.Regexp
-	jestFailPattern    *regexp.Regexp
-	bunTestPattern     *regexp.Regexp
+	tscErrorPattern      *regexp.Regexp
+	tscDiagnosticPattern *regexp.Regexp
+	tscPrettyPattern     *regexp.Regexp
+	tscGlobalPattern     *regexp.Regexp
+	eslintMessagePattern *regexp.Regexp
+	eslintPattern        *regexp.Regexp
+	testFailPattern      *regexp.Regexp
+	testPassPattern      *regexp.Regexp
+	jestFailPattern      *regexp.Regexp
+	bunTestPattern       *regexp.Regexp
+	goDiagnosticPattern  *regexp.Regexp
+	mypyPattern          *regexp.Regexp
+	
+	// User-defined parsers and output formats for commands, from kwatch.yaml
+	patterns map[CommandType]*PatternParser
+	formats  map[CommandType]string
 }
 
 // NewParser creates a new parser instance with compiled regex patterns
@@ -23,96 +33,215 @@ func NewParser() *Parser {
 		// TypeScript patterns
 		tscErrorPattern: regexp.MustCompile(`Found (\d+) errors?`),
 		
+		// Matches "src/a.ts(10,5): error TS2322: message"
+		tscDiagnosticPattern: regexp.MustCompile(`^(.+?)\((\d+),(\d+)\): (error|warning|message) (TS\d+): (.*)$`),
+		
+		// Matches pretty output "src/a.ts:10:5 - error TS2322: message"
+		tscPrettyPattern: regexp.MustCompile(`^(.+?):(\d+):(\d+) - (error|warning|message) (TS\d+): (.*)$`),
+		
+		// Matches location-less diagnostics "error TS5083: message"
+		tscGlobalPattern: regexp.MustCompile(`^(error|warning|message) (TS\d+): (.*)$`),
+		
 		// ESLint patterns - matches "✖ 3 problems (1 error, 2 warnings)"
 		eslintPattern: regexp.MustCompile(`✖ (\d+) problems?`),
 		
+		// ESLint stylish message lines - matches "  10:5  error  message  rule-id"
+		eslintMessagePattern: regexp.MustCompile(`^\s+(\d+):(\d+)\s+(error|warning)\s+(.+?)(?:\s{2,}(\S+))?\s*$`),
+		
 		// Test patterns for various test runners
 		testFailPattern:    regexp.MustCompile(`(\d+) failing`),
 		testPassPattern:    regexp.MustCompile(`(\d+) passing`),
 		jestFailPattern:    regexp.MustCompile(`FAIL|Failed|failed`),
 		bunTestPattern:     regexp.MustCompile(`(\d+) fail`),
+		
+		// Go compiler and vet diagnostics - matches "./main.go:10:2: message"
+		goDiagnosticPattern: regexp.MustCompile(`^(?:vet: )?(\S+\.go):(\d+)(?::(\d+))?: (.*)$`),
+		
+		// mypy diagnostics - matches "app/main.py:10: error: message  [code]"
+		mypyPattern: regexp.MustCompile(`^(.+?\.pyi?):(\d+)(?::(\d+))?: (error|warning|note): (.*?)(?:  \[([\w-]+)\])?$`),
+	}
+}
+
+// SetPatternParser makes the parser use user-defined patterns for a command
+func (p *Parser) SetPatternParser(cmdType CommandType, parser *PatternParser) {
+	if p.patterns == nil {
+		p.patterns = make(map[CommandType]*PatternParser)
+	}
+	p.patterns[cmdType] = parser
+}
+
+// PatternParser returns the user-defined parser of a command, if any
+func (p *Parser) PatternParser(cmdType CommandType) *PatternParser {
+	return p.patterns[cmdType]
+}
+
+// definition describes how a command's output is parsed: its declared
+// format, its patterns, or nothing for the built-in parsers
+func (p *Parser) definition(cmdType CommandType) string {
+	if format := p.Format(cmdType); format != "" {
+		return "format " + format
+	}
+	if patterns := p.PatternParser(cmdType); patterns != nil {
+		return patterns.String()
 	}
+	return ""
 }
 
-// ParseTypeScriptOutput parses TypeScript compiler output
-func (p *Parser) ParseTypeScriptOutput(output string) (passed bool, issueCount int) {
+// ParseTypeScriptOutput parses TypeScript compiler output into diagnostics
+func (p *Parser) ParseTypeScriptOutput(output string) (passed bool, diagnostics []Diagnostic) {
 	// Clean the output
-	output = strings.TrimSpace(output)
+	output = strings.TrimSpace(stripANSI(output))
 	
 	// If output is empty, assume success
 	if output == "" {
-		return true, 0
+		return true, nil
+	}
+	
+	// Extract one diagnostic per "error TS" line
+	for _, line := range strings.Split(output, "\n") {
+		if diagnostic, ok := p.parseTypeScriptLine(strings.TrimRight(line, "\r")); ok {
+			diagnostics = append(diagnostics, diagnostic)
+		}
 	}
 	
-	// Look for error count pattern
+	if len(diagnostics) > 0 {
+		errorCount, _ := CountDiagnostics(diagnostics)
+		return errorCount == 0, diagnostics
+	}
+	
+	// Fall back to the summary line when no diagnostics could be extracted
 	matches := p.tscErrorPattern.FindStringSubmatch(output)
 	if len(matches) >= 2 {
 		if count, err := strconv.Atoi(matches[1]); err == nil {
-			return count == 0, count
+			return count == 0, nil
 		}
 	}
 	
 	// Check for common success indicators
 	if strings.Contains(output, "No errors found") {
-		return true, 0
-	}
-	
-	// Check for error indicators
-	if strings.Contains(output, "error TS") || strings.Contains(output, "Found") {
-		// Try to extract error count from lines containing "error TS"
-		lines := strings.Split(output, "\n")
-		errorCount := 0
-		for _, line := range lines {
-			if strings.Contains(line, "error TS") {
-				errorCount++
-			}
-		}
-		return errorCount == 0, errorCount
+		return true, nil
 	}
 	
 	// If we can't determine, assume success if no obvious errors
-	return !strings.Contains(strings.ToLower(output), "error"), 0
+	return !strings.Contains(strings.ToLower(output), "error"), nil
 }
 
-// ParseLintOutput parses ESLint/linter output
-func (p *Parser) ParseLintOutput(output string) (passed bool, issueCount int) {
+// parseTypeScriptLine parses a single tsc diagnostic line in either
+// "file(line,col): error TS1234: message" or pretty "file:line:col - error TS1234: message" form
+func (p *Parser) parseTypeScriptLine(line string) (Diagnostic, bool) {
+	matches := p.tscDiagnosticPattern.FindStringSubmatch(line)
+	if matches == nil {
+		matches = p.tscPrettyPattern.FindStringSubmatch(line)
+	}
+	if matches != nil {
+		lineNum, _ := strconv.Atoi(matches[2])
+		column, _ := strconv.Atoi(matches[3])
+		return Diagnostic{
+			File:     matches[1],
+			Line:     lineNum,
+			Column:   column,
+			Severity: typeScriptSeverity(matches[4]),
+			Rule:     matches[5],
+			Message:  strings.TrimSpace(matches[6]),
+			Tool:     "tsc",
+		}, true
+	}
+	
+	// Global diagnostics (e.g. tsconfig problems) have no location
+	if matches := p.tscGlobalPattern.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+		return Diagnostic{
+			Severity: typeScriptSeverity(matches[1]),
+			Rule:     matches[2],
+			Message:  strings.TrimSpace(matches[3]),
+			Tool:     "tsc",
+		}, true
+	}
+	
+	return Diagnostic{}, false
+}
+
+// typeScriptSeverity maps a tsc category to a diagnostic severity
+func typeScriptSeverity(category string) Severity {
+	switch category {
+	case "error":
+		return SeverityError
+	case "warning":
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
+
+// ParseLintOutput parses ESLint/linter output into diagnostics
+func (p *Parser) ParseLintOutput(output string) (passed bool, diagnostics []Diagnostic) {
 	// Clean the output
-	output = strings.TrimSpace(output)
+	output = strings.TrimSpace(stripANSI(output))
 	
 	// If output is empty, assume success
 	if output == "" {
-		return true, 0
+		return true, nil
+	}
+	
+	// Parse ESLint stylish output: a file path header followed by indented messages
+	diagnostics = p.parseStylishOutput(output)
+	if len(diagnostics) > 0 {
+		return false, diagnostics
 	}
 	
 	// Look for ESLint summary pattern
 	matches := p.eslintPattern.FindStringSubmatch(output)
 	if len(matches) >= 2 {
 		if count, err := strconv.Atoi(matches[1]); err == nil {
-			return count == 0, count
+			return count == 0, nil
 		}
 	}
 	
 	// Look for other common lint patterns
 	if strings.Contains(output, "✓") && !strings.Contains(output, "✖") {
-		return true, 0
+		return true, nil
+	}
+	
+	// Output of other linters fails when it mentions errors or warnings
+	if p.CountLintIssues(output) > 0 {
+		return false, nil
 	}
 	
-	// Check for Biome linter patterns
+	// Check for success indicators
+	if strings.Contains(output, "No issues found") || 
+	   strings.Contains(output, "0 errors") ||
+	   strings.Contains(output, "All files pass linting") {
+		return true, nil
+	}
+	
+	// If we can't determine, assume failure if there's substantial output
+	return len(output) < 100, nil
+}
+
+// CountLintIssues counts the issues in lint output that no diagnostics
+// could be extracted from: the count of an ESLint or Biome summary, or
+// else the lines mentioning an error or warning
+func (p *Parser) CountLintIssues(output string) int {
+	output = strings.TrimSpace(stripANSI(output))
+
+	// Look for ESLint summary pattern
+	if matches := p.eslintPattern.FindStringSubmatch(output); len(matches) >= 2 {
+		if count, err := strconv.Atoi(matches[1]); err == nil {
+			return count
+		}
+	}
+
+	// Check for Biome linter patterns ("Found X errors")
 	if strings.Contains(output, "Found ") {
-		// Try to extract count from "Found X errors"
-		re := regexp.MustCompile(`Found (\d+) errors?`)
-		matches := re.FindStringSubmatch(output)
-		if len(matches) >= 2 {
+		if matches := p.tscErrorPattern.FindStringSubmatch(output); len(matches) >= 2 {
 			if count, err := strconv.Atoi(matches[1]); err == nil {
-				return count == 0, count
+				return count
 			}
 		}
 	}
-	
+
 	// Count lines with error/warning indicators
-	lines := strings.Split(output, "\n")
-	issueCount = 0
-	for _, line := range lines {
+	issueCount := 0
+	for _, line := range strings.Split(output, "\n") {
 		line = strings.TrimSpace(line)
 		if strings.Contains(line, "error") || strings.Contains(line, "warning") {
 			// Skip lines that are just headers or summaries
@@ -121,29 +250,62 @@ func (p *Parser) ParseLintOutput(output string) (passed bool, issueCount int) {
 			}
 		}
 	}
-	
-	// If we found issues, use that count
-	if issueCount > 0 {
-		return false, issueCount
-	}
-	
-	// Check for success indicators
-	if strings.Contains(output, "No issues found") || 
-	   strings.Contains(output, "0 errors") ||
-	   strings.Contains(output, "All files pass linting") {
-		return true, 0
+	return issueCount
+}
+
+// parseStylishOutput extracts diagnostics from ESLint's default stylish formatter
+func (p *Parser) parseStylishOutput(output string) []Diagnostic {
+	var diagnostics []Diagnostic
+	currentFile := ""
+	
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		
+		// Messages are indented below their file header
+		if matches := p.eslintMessagePattern.FindStringSubmatch(line); matches != nil {
+			if currentFile == "" {
+				continue
+			}
+			lineNum, _ := strconv.Atoi(matches[1])
+			column, _ := strconv.Atoi(matches[2])
+			severity := SeverityWarning
+			if matches[3] == "error" {
+				severity = SeverityError
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				File:     currentFile,
+				Line:     lineNum,
+				Column:   column,
+				Severity: severity,
+				Message:  strings.TrimSpace(matches[4]),
+				Rule:     matches[5],
+				Tool:     "eslint",
+			})
+			continue
+		}
+		
+		// Unindented lines are file headers, except for the summary footer
+		if line == trimmed && !strings.HasPrefix(trimmed, "✖") && !strings.HasPrefix(trimmed, ">") {
+			currentFile = trimmed
+		} else if strings.HasPrefix(trimmed, "✖") {
+			currentFile = ""
+		}
 	}
 	
-	// If we can't determine, assume failure if there's substantial output
-	return len(output) < 100, 0
+	return diagnostics
 }
 
 // TestResult represents detailed test execution results
 type TestResult struct {
-	Passed      bool
-	TotalTests  int
-	PassedTests int
-	FailedTests int
+	Passed       bool
+	TotalTests   int
+	PassedTests  int
+	FailedTests  int
+	SkippedTests int
 }
 
 // ParseTestOutput parses test runner output (Jest, Mocha, Bun test, etc.)
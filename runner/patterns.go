@@ -0,0 +1,143 @@
+package runner
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PatternParser extracts diagnostics and counts from the output of tools
+// kwatch has no built-in parser for, using patterns from kwatch.yaml.
+// Diagnostic patterns match one diagnostic per line with the named groups
+// file, line, col, severity, rule and message. Summary patterns match a
+// line reporting counts with the named groups errors, warnings or issues.
+type PatternParser struct {
+	tool        string
+	diagnostics []*regexp.Regexp
+	summaries   []*regexp.Regexp
+}
+
+// NewPatternParser compiles the diagnostic and summary patterns of a tool
+func NewPatternParser(tool string, diagnostics, summaries []string) (*PatternParser, error) {
+	parser := &PatternParser{tool: tool}
+
+	for _, pattern := range diagnostics {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid diagnostic pattern %q: %w", pattern, err)
+		}
+		parser.diagnostics = append(parser.diagnostics, re)
+	}
+
+	for _, pattern := range summaries {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid summary pattern %q: %w", pattern, err)
+		}
+		parser.summaries = append(parser.summaries, re)
+	}
+
+	return parser, nil
+}
+
+// String lists the parser's patterns
+func (p *PatternParser) String() string {
+	var b strings.Builder
+	for _, re := range p.diagnostics {
+		fmt.Fprintf(&b, "diagnostic %q\n", re.String())
+	}
+	for _, re := range p.summaries {
+		fmt.Fprintf(&b, "summary %q\n", re.String())
+	}
+	return b.String()
+}
+
+// Parse returns the diagnostics found in the output and the issue count.
+// The count comes from the last summary line when one matches, otherwise
+// it is the number of diagnostics.
+func (p *PatternParser) Parse(output string) ([]Diagnostic, int) {
+	var diagnostics []Diagnostic
+	summary := -1
+
+	for _, line := range strings.Split(stripANSI(output), "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		for _, re := range p.diagnostics {
+			if groups := namedGroups(re, line); groups != nil {
+				diagnostics = append(diagnostics, p.diagnostic(groups))
+				break
+			}
+		}
+
+		for _, re := range p.summaries {
+			if groups := namedGroups(re, line); groups != nil {
+				summary = summaryCount(groups)
+				break
+			}
+		}
+	}
+
+	if summary >= 0 {
+		return diagnostics, summary
+	}
+	return diagnostics, len(diagnostics)
+}
+
+// diagnostic builds a diagnostic from the named groups of a match
+func (p *PatternParser) diagnostic(groups map[string]string) Diagnostic {
+	lineNum, _ := strconv.Atoi(groups["line"])
+	column, _ := strconv.Atoi(groups["col"])
+	return Diagnostic{
+		File:     groups["file"],
+		Line:     lineNum,
+		Column:   column,
+		Rule:     groups["rule"],
+		Severity: patternSeverity(groups["severity"]),
+		Message:  strings.TrimSpace(groups["message"]),
+		Tool:     p.tool,
+	}
+}
+
+// namedGroups matches a line and returns its named groups, or nil if the
+// line does not match
+func namedGroups(re *regexp.Regexp, line string) map[string]string {
+	matches := re.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+
+	groups := make(map[string]string)
+	for i, name := range re.SubexpNames() {
+		if name != "" {
+			groups[name] = matches[i]
+		}
+	}
+	return groups
+}
+
+// summaryCount returns the issue count of a summary line: the issues group
+// if present, otherwise errors plus warnings
+func summaryCount(groups map[string]string) int {
+	if issues, ok := groups["issues"]; ok {
+		count, _ := strconv.Atoi(issues)
+		return count
+	}
+
+	errors, _ := strconv.Atoi(groups["errors"])
+	warnings, _ := strconv.Atoi(groups["warnings"])
+	return errors + warnings
+}
+
+// patternSeverity maps a severity captured by a pattern to a diagnostic
+// severity. Diagnostics without a severity are errors.
+func patternSeverity(severity string) Severity {
+	switch strings.ToLower(severity) {
+	case "", "error", "err", "fatal", "e":
+		return SeverityError
+	case "warning", "warn", "w":
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"context"
+	"os/exec"
+	"sync/atomic"
+	"time"
+)
+
+// defaultKillGracePeriod is how long a command's processes get to exit
+// after SIGTERM before they are killed
+const defaultKillGracePeriod = 5 * time.Second
+
+// runProcessGroup runs cmd in its own process group and waits for it.
+// When ctx is done the whole group is sent SIGTERM, then SIGKILL once the
+// grace period has passed or the command has exited, so grandchildren such
+// as tsc or jest workers started through npx and npm do not outlive the
+// command. The group is only signalled before its leader is reaped, while
+// no other group can have its ID. A non-nil limiter applies resource
+// limits to the command.
+func runProcessGroup(ctx context.Context, cmd *exec.Cmd, grace time.Duration, limiter *limiter) error {
+	setProcessGroup(cmd)
+	if err := limiter.prepare(cmd); err != nil {
+		return err
+	}
+	// Stop waiting for output held open by stray descendants
+	cmd.WaitDelay = grace
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan struct{})
+	signalled := make(chan struct{})
+	var reaped atomic.Bool
+	go func() {
+		defer close(signalled)
+		select {
+		case <-exited:
+			return
+		case <-ctx.Done():
+		}
+
+		terminateProcessGroup(cmd)
+		// Descendants may ignore SIGTERM even after the leader has exited
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-exited:
+			if reaped.Load() {
+				return
+			}
+		}
+		killProcessGroup(cmd)
+	}()
+
+	// Where the leader cannot be waited for without reaping it, a signal
+	// sent as it is reaped may reach a new group with the same ID
+	if waitUnreaped(cmd.Process.Pid) {
+		close(exited)
+		<-signalled
+		return cmd.Wait()
+	}
+
+	err := cmd.Wait()
+	reaped.Store(true)
+	close(exited)
+	return err
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package runner
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the command in a new process group
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// terminateProcessGroup stops the command. Windows has no SIGTERM, so
+// the process is killed directly.
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// killProcessGroup forcibly kills the command
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pytestCountPattern matches one "3 passed" part of a pytest summary line
+var pytestCountPattern = regexp.MustCompile(`(\d+) (passed|failed|errors?|skipped|xfailed|xpassed|deselected|warnings?)`)
+
+// pytestSummaryPattern matches the final pytest summary line, with or
+// without the "=" framing: "=== 1 failed, 2 passed in 0.12s ==="
+var pytestSummaryPattern = regexp.MustCompile(`^=*\s*(\d+ \w+(?:, \d+ \w+)*) in [\d.]+s\b`)
+
+// ruffViolation is one entry of ruff check --output-format json output
+type ruffViolation struct {
+	Code     *string         `json:"code"`
+	Message  string          `json:"message"`
+	Filename string          `json:"filename"`
+	Fix      json.RawMessage `json:"fix"`
+	Location struct {
+		Row    int `json:"row"`
+		Column int `json:"column"`
+	} `json:"location"`
+}
+
+// ParsePytestOutput reads the test counts from pytest's summary line.
+// Errors during collection or fixtures count as failed tests, and
+// expected failures as skipped ones.
+func (p *Parser) ParsePytestOutput(output string) TestResult {
+	var summary string
+	for _, line := range strings.Split(stripANSI(output), "\n") {
+		if matches := pytestSummaryPattern.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			summary = matches[1]
+		}
+	}
+
+	var result TestResult
+	for _, matches := range pytestCountPattern.FindAllStringSubmatch(summary, -1) {
+		count, _ := strconv.Atoi(matches[1])
+		switch matches[2] {
+		case "passed", "xpassed":
+			result.PassedTests += count
+		case "failed", "error", "errors":
+			result.FailedTests += count
+		case "skipped", "xfailed":
+			result.SkippedTests += count
+		}
+	}
+	result.TotalTests = result.PassedTests + result.FailedTests + result.SkippedTests
+	result.Passed = result.FailedTests == 0
+	return result
+}
+
+// ParseMypyOutput parses mypy's "file.py:line: error: message  [code]" lines
+func (p *Parser) ParseMypyOutput(output string) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, line := range strings.Split(stripANSI(output), "\n") {
+		matches := p.mypyPattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if matches == nil {
+			continue
+		}
+
+		lineNum, _ := strconv.Atoi(matches[2])
+		column, _ := strconv.Atoi(matches[3])
+		severity := SeverityInfo
+		switch matches[4] {
+		case "error":
+			severity = SeverityError
+		case "warning":
+			severity = SeverityWarning
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     matches[1],
+			Line:     lineNum,
+			Column:   column,
+			Rule:     matches[6],
+			Severity: severity,
+			Message:  strings.TrimSpace(matches[5]),
+			Tool:     "mypy",
+		})
+	}
+	return diagnostics
+}
+
+// ParseRuffOutput parses ruff check --output-format json output. Output
+// that is not a JSON report yields no diagnostics.
+func (p *Parser) ParseRuffOutput(output string) []Diagnostic {
+	start := jsonArrayStart(output)
+	if start < 0 {
+		return nil
+	}
+
+	var violations []ruffViolation
+	if err := json.NewDecoder(strings.NewReader(output[start:])).Decode(&violations); err != nil {
+		return nil
+	}
+
+	diagnostics := make([]Diagnostic, 0, len(violations))
+	for _, violation := range violations {
+		diagnostic := Diagnostic{
+			File:     violation.Filename,
+			Line:     violation.Location.Row,
+			Column:   violation.Location.Column,
+			Severity: SeverityError,
+			Message:  violation.Message,
+			Tool:     "ruff",
+			Fixable:  len(violation.Fix) > 0 && string(violation.Fix) != "null",
+		}
+		if violation.Code != nil {
+			diagnostic.Rule = *violation.Code
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
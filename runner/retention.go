@@ -0,0 +1,204 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"kwatch/config"
+)
+
+// compactionInterval is how often a store is compacted when age based
+// retention is configured, even if it has not grown past MaxEntries
+const compactionInterval = time.Hour
+
+// RetentionPolicy decides which results a history store keeps
+type RetentionPolicy struct {
+	// MaxEntries keeps only the newest results (0 for no limit)
+	MaxEntries int
+	// MaxAge drops results older than this (0 for no limit)
+	MaxAge time.Duration
+	// FailuresOnlyAfter drops passing results older than this (0 to keep them)
+	FailuresOnlyAfter time.Duration
+}
+
+// PruneStats reports what a prune removed
+type PruneStats struct {
+	Kept           int `json:"kept"`
+	Removed        int `json:"removed"`
+	OutputsRemoved int `json:"outputs_removed"`
+}
+
+// RetentionFromConfig builds a retention policy from the kwatch configuration
+func RetentionFromConfig(kwatchConfig *config.Config) RetentionPolicy {
+	maxEntries, maxAge, failuresOnlyAfter := kwatchConfig.HistoryRetention()
+	return RetentionPolicy{
+		MaxEntries:        maxEntries,
+		MaxAge:            maxAge,
+		FailuresOnlyAfter: failuresOnlyAfter,
+	}
+}
+
+// IsZero reports whether the policy keeps everything
+func (p RetentionPolicy) IsZero() bool {
+	return p.MaxEntries == 0 && p.MaxAge == 0 && p.FailuresOnlyAfter == 0
+}
+
+// Apply returns the results the policy keeps, preserving their order.
+// Results are expected oldest first, as they are written to the store.
+func (p RetentionPolicy) Apply(results []CommandResult, now time.Time) []CommandResult {
+	kept := make([]CommandResult, 0, len(results))
+	for _, result := range results {
+		age := now.Sub(result.Timestamp)
+		if p.MaxAge > 0 && age > p.MaxAge {
+			continue
+		}
+		if p.FailuresOnlyAfter > 0 && age > p.FailuresOnlyAfter && result.State() == StatusPassed {
+			continue
+		}
+		kept = append(kept, result)
+	}
+
+	if p.MaxEntries > 0 && len(kept) > p.MaxEntries {
+		kept = kept[len(kept)-p.MaxEntries:]
+	}
+	return kept
+}
+
+// compactionDue reports whether a store with the given index should be
+// compacted. Stores are allowed to grow a quarter past MaxEntries before
+// being compacted so pruning is not repeated on every write.
+func (p RetentionPolicy) compactionDue(index historyIndex, now time.Time) bool {
+	if p.IsZero() {
+		return false
+	}
+
+	if p.MaxEntries > 0 {
+		total := 0
+		for _, segment := range index.Segments {
+			total += segment.Count
+		}
+		if total > p.MaxEntries+p.MaxEntries/4 {
+			return true
+		}
+	}
+
+	if p.MaxAge > 0 || p.FailuresOnlyAfter > 0 {
+		return now.Sub(index.Compacted) > compactionInterval
+	}
+	return false
+}
+
+// Prune removes the results the policy does not keep, rewrites the rest
+// into fresh segments and deletes outputs that are no longer referenced
+func (s *HistoryStore) Prune(policy RetentionPolicy) (PruneStats, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := s.lock()
+	if err != nil {
+		return PruneStats{}, err
+	}
+	defer unlock()
+
+	return s.prune(policy, time.Now())
+}
+
+// prune implements Prune. The caller must hold the store's locks.
+func (s *HistoryStore) prune(policy RetentionPolicy, now time.Time) (PruneStats, error) {
+	index, err := s.readIndex()
+	if err != nil {
+		return PruneStats{}, err
+	}
+
+	var entries []CommandResult
+	for _, segment := range index.Segments {
+		results, _, _, err := s.readSegment(segment.Name, 0, segment.Count)
+		if err != nil {
+			return PruneStats{}, err
+		}
+		entries = append(entries, results...)
+	}
+
+	kept := policy.Apply(entries, now)
+	stats := PruneStats{Kept: len(kept), Removed: len(entries) - len(kept)}
+
+	// Move outputs still stored inline into the object store
+	inline := false
+	for i := range kept {
+		if kept[i].Output != "" {
+			inline = true
+			if kept[i], err = s.storeOutput(kept[i]); err != nil {
+				return stats, err
+			}
+		}
+	}
+
+	if stats.Removed > 0 || inline {
+		// Write new segments rather than rewriting in place, so other
+		// processes notice the old ones are gone and reload
+		index.ensureNextSegment()
+		compacted := historyIndex{NextSegment: index.NextSegment, Compacted: now}
+		for start := 0; start < len(kept); start += maxSegmentEntries {
+			end := min(start+maxSegmentEntries, len(kept))
+			segment, err := s.writeSegment(compacted.newSegmentName(), kept[start:end])
+			if err != nil {
+				return stats, err
+			}
+			compacted.Segments = append(compacted.Segments, segment)
+		}
+		if err := s.writeIndex(compacted); err != nil {
+			return stats, err
+		}
+		for _, segment := range index.Segments {
+			os.Remove(filepath.Join(s.dir, segment.Name))
+		}
+	} else {
+		index.Compacted = now
+		if err := s.writeIndex(index); err != nil {
+			return stats, err
+		}
+	}
+
+	if err := s.pruneRuns(kept); err != nil {
+		return stats, err
+	}
+
+	removed, err := s.removeUnusedOutputs(kept)
+	stats.OutputsRemoved = removed
+	return stats, err
+}
+
+// writeSegment writes results to a new segment file
+func (s *HistoryStore) writeSegment(name string, results []CommandResult) (historySegment, error) {
+	segment := historySegment{Name: name}
+
+	var data []byte
+	for _, result := range results {
+		line, err := json.Marshal(result)
+		if err != nil {
+			return segment, fmt.Errorf("failed to encode result: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+
+		if segment.First.IsZero() || result.Timestamp.Before(segment.First) {
+			segment.First = result.Timestamp
+		}
+		if result.Timestamp.After(segment.Last) {
+			segment.Last = result.Timestamp
+		}
+	}
+	segment.Count = len(results)
+	segment.Bytes = int64(len(data))
+
+	path := filepath.Join(s.dir, name)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return segment, fmt.Errorf("failed to write history segment: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return segment, fmt.Errorf("failed to write history segment: %w", err)
+	}
+	return segment, nil
+}
@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// DefaultRetryBackoff is the delay before the first retry when a command
+// does not set one; each further retry waits twice as long
+const DefaultRetryBackoff = time.Second
+
+// maxRetryBackoff caps the delay between attempts
+const maxRetryBackoff = 30 * time.Second
+
+// RetryPolicy decides whether a failed command runs again
+type RetryPolicy struct {
+	// Retries is how many times a failed run is retried (0 to never retry)
+	Retries int `json:"retries,omitempty"`
+	// ExitCodes and Output restrict retries to failures that exited with
+	// one of the codes or whose output matches one of the regular
+	// expressions. Without either, any failure is retried.
+	ExitCodes []int            `json:"exit_codes,omitempty"`
+	Output    []*regexp.Regexp `json:"output,omitempty"`
+	// Backoff is the delay before the first retry (default 1s)
+	Backoff time.Duration `json:"backoff,omitempty"`
+}
+
+// Attempt records one run of a command that was retried
+type Attempt struct {
+	Status   ResultStatus  `json:"status"`
+	ExitCode int           `json:"exit_code"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// attemptOf summarizes a result as an attempt
+func attemptOf(result CommandResult) Attempt {
+	return Attempt{
+		Status:   result.State(),
+		ExitCode: result.ExitCode,
+		Duration: result.Duration,
+		Error:    result.Error,
+	}
+}
+
+// Retried reports whether the command ran more than once
+func (r CommandResult) Retried() bool {
+	return len(r.Attempts) > 1
+}
+
+// AttemptSummary describes how many attempts a retried command took, as
+// in "passed on attempt 2", or returns an empty string if it ran once
+func (r CommandResult) AttemptSummary() string {
+	switch {
+	case !r.Retried():
+		return ""
+	case r.State() == StatusPassed:
+		return fmt.Sprintf("on attempt %d", len(r.Attempts))
+	default:
+		return fmt.Sprintf("after %d attempts", len(r.Attempts))
+	}
+}
+
+// shouldRetry reports whether a result is a failure the policy retries.
+// Cancelled runs and runs that already used every retry are final.
+func (p RetryPolicy) shouldRetry(result CommandResult, attempt int) bool {
+	if attempt > p.Retries {
+		return false
+	}
+	if state := result.State(); state != StatusFailed && state != StatusTimedOut {
+		return false
+	}
+	if len(p.ExitCodes) == 0 && len(p.Output) == 0 {
+		return true
+	}
+
+	for _, code := range p.ExitCodes {
+		if code == result.ExitCode {
+			return true
+		}
+	}
+	for _, re := range p.Output {
+		if re.MatchString(result.Output) {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns how long to wait before the given retry (1 for the first)
+func (p RetryPolicy) delay(retry int) time.Duration {
+	delay := p.Backoff
+	if delay <= 0 {
+		delay = DefaultRetryBackoff
+	}
+	for i := 1; i < retry && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
+
+// wait sleeps for the delay before a retry. It returns false if the
+// context was cancelled first.
+func (p RetryPolicy) wait(ctx context.Context, retry int) bool {
+	timer := time.NewTimer(p.delay(retry))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -2,8 +2,10 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -19,6 +21,16 @@ type Runner struct {
 	mutex        sync.RWMutex
 	kwatchConfig *config.Config
 	githubClient *GitHubClient
+	scheduler    *Scheduler
+	tracker      *runTracker
+	subscribers  subscriberSet[OutputLine]
+	events       subscriberSet[Event]
+	results      subscriberSet[CommandResult]
+	cache        *ResultCache
+	baselines    baselines
+	flaky        FlakyPolicy
+	trends       TrendPolicy
+	totals       usageTotals
 }
 
 // NewRunner creates a new runner instance
@@ -28,6 +40,47 @@ func NewRunner(config RunnerConfig, kwatchConfig *config.Config) *Runner {
 		history:      &ResultHistory{},
 		parser:       NewParser(),
 		kwatchConfig: kwatchConfig,
+		scheduler:    NewScheduler(config.MaxParallel),
+		tracker:      newRunTracker(),
+	}
+	
+	// Compile the output parsers declared in the config
+	if kwatchConfig != nil {
+		for name, configCmd := range kwatchConfig.Commands {
+			if configCmd.Parser.Format != "" {
+				runner.parser.SetFormat(CommandType(name), configCmd.Parser.Format)
+				continue
+			}
+			if configCmd.Parser.IsZero() {
+				continue
+			}
+			patterns, err := NewPatternParser(name, configCmd.Parser.Diagnostics, configCmd.Parser.Summary)
+			if err == nil {
+				runner.parser.SetPatternParser(CommandType(name), patterns)
+			}
+		}
+	}
+	
+	if kwatchConfig != nil {
+		runner.flaky = FlakyPolicyFromConfig(kwatchConfig)
+		runner.trends = TrendPolicyFromConfig(kwatchConfig)
+	}
+	
+	// Persist history in the project so it is shared between frontends
+	if config.WorkingDir != "" {
+		if store, err := OpenHistoryStore(HistoryDir(config.WorkingDir)); err == nil {
+			if kwatchConfig != nil {
+				store.SetRetention(RetentionFromConfig(kwatchConfig))
+			}
+			runner.history = NewResultHistory(store)
+		}
+	}
+	
+	// Cache passing results of commands with declared inputs
+	if config.WorkingDir != "" {
+		if cache, err := OpenResultCache(CacheDir(config.WorkingDir)); err == nil {
+			runner.cache = cache
+		}
 	}
 	
 	// Initialize GitHub client if possible
@@ -47,10 +100,102 @@ func (r *Runner) RunCommand(ctx context.Context, command Command) CommandResult
 		return r.runGitHubCommand(ctx, command)
 	}
 	
+	// Number this run so a newer run of the same command can supersede it
+	ctx, generation, finish := r.tracker.begin(ctx, command.Type)
+	defer finish()
+	
+	// Reuse the result of an earlier passing run with identical inputs
+	cacheKey := r.cacheKey(command)
+	if cacheKey != "" {
+		if cached, hit := r.cache.Get(command.Type, cacheKey); hit {
+			cached.Type = command.Type
+			cached.Cached = true
+			cached.Attempts = nil
+			cached.Timestamp = time.Now()
+			cached.Generation = generation
+			cached = r.recordResult(command, cached)
+			if !cached.Superseded {
+				r.updateBaseline(command.Type, cached)
+			}
+			return cached
+		}
+	}
+	
+	// Wait for free slots so no more than MaxParallel commands run at once
+	slots, err := r.scheduler.Acquire(ctx, command.Weight, command.Priority)
+	if err != nil {
+		result := CommandResult{
+			Type:       command.Type,
+			Command:    command.Command,
+			Timestamp:  time.Now(),
+			Error:      fmt.Sprintf("command was not started: %v", err),
+			Generation: generation,
+		}
+		return r.recordResult(command, result)
+	}
+	defer func() { r.scheduler.Release(slots) }()
+	
+	// Run the command, retrying failures as its retry policy allows. The
+	// slots are given back while waiting to retry, so other commands can
+	// run in the meantime.
+	result := r.runAttempt(ctx, command, generation)
+	attempts := []Attempt{attemptOf(result)}
+	for command.Retry.shouldRetry(result, len(attempts)) {
+		r.scheduler.Release(slots)
+		slots = 0
+		if !command.Retry.wait(ctx, len(attempts)) {
+			break
+		}
+		if slots, err = r.scheduler.Acquire(ctx, command.Weight, command.Priority); err != nil {
+			break
+		}
+		result = r.runAttempt(ctx, command, generation)
+		attempts = append(attempts, attemptOf(result))
+	}
+	if len(attempts) > 1 {
+		result.Attempts = attempts
+	}
+	terminated := result.State() == StatusTimedOut || result.State() == StatusCancelled
+	
+	// Only cache passing runs whose inputs did not change while running
+	if cacheKey != "" && result.State() == StatusPassed && r.cacheKey(command) == cacheKey {
+		r.cache.Put(command.Type, cacheKey, result)
+	}
+	
+	if terminated {
+		return r.recordResult(command, result)
+	}
+	
+	// Incremental results extend the last full result
+	if command.Incremental() {
+		result.ChangedFiles = command.ChangedFiles
+		result = r.mergeIncremental(command, result)
+	}
+	
+	result = r.recordResult(command, result)
+	if !result.Superseded {
+		r.updateBaseline(command.Type, result)
+	}
+	return result
+}
+
+// runAttempt runs a command once, parses its output and decides whether
+// it passed
+func (r *Runner) runAttempt(ctx context.Context, command Command, generation uint64) CommandResult {
 	start := time.Now()
 	result := CommandResult{
-		Command:   command.Command,
-		Timestamp: start,
+		Type:       command.Type,
+		Command:    command.Command,
+		Timestamp:  start,
+		Generation: generation,
+	}
+	
+	// Record the inputs tests ran on, so flaky tests can be told apart
+	// from tests broken by a change
+	if command.reportsTestCases() && r.config.WorkingDir != "" {
+		if hash, err := WorkingTreeHash(r.config.WorkingDir, command.outputFiles()); err == nil {
+			result.TreeHash = hash
+		}
 	}
 
 	// Create command context with timeout
@@ -62,44 +207,186 @@ func (r *Runner) RunCommand(ctx context.Context, command Command) CommandResult
 	cmdCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	// Reports left over from earlier runs must not be read as this run's
+	reports, err := r.snapshotReports(command)
+	if err != nil {
+		result.Error = err.Error()
+		result.ExitCode = -1
+		result.Status = StatusFailed
+		return result
+	}
+
 	// Execute command
-	cmd := exec.CommandContext(cmdCtx, command.Command, command.Args...)
+	cmd := exec.Command(command.Command, command.resolvedArgs()...)
 	if r.config.WorkingDir != "" {
 		cmd.Dir = r.config.WorkingDir
 	}
 
-	output, err := cmd.CombinedOutput()
+	// Stream output to subscribers line by line while collecting it
+	output := newLineWriter(func(line string) {
+		r.publish(OutputLine{
+			Command:    command.Type,
+			Generation: generation,
+			Text:       line,
+			Timestamp:  time.Now(),
+		})
+	})
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	limits := newLimiter(command.Limits)
+	defer limits.release()
+	
+	err = runProcessGroup(cmdCtx, cmd, r.killGracePeriod(), limits)
+	output.Flush()
 	result.Duration = time.Since(start)
-	result.Output = string(output)
+	result.Output = output.String()
 
 	if err != nil {
 		result.Error = err.Error()
 	}
+	result.ExitCode = -1
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	result.setUsage(limits.groupUsage(processUsage(cmd.ProcessState)))
+	exceeded := limits.exceeded(cmd.ProcessState, result.Output)
 	
-	// Parse output based on command type
-	if command.Type == TestRunner {
-		testResult := r.parser.ParseTestOutput(result.Output)
-		result.Passed = testResult.Passed
-		result.IssueCount = testResult.FailedTests
-		result.TotalTests = testResult.TotalTests
-		result.PassedTests = testResult.PassedTests
-		result.FailedTests = testResult.FailedTests
-	} else {
-		passed, issueCount := r.parseCommandOutput(command.Type, result.Output)
-		result.Passed = passed
-		result.IssueCount = issueCount
+	// Record why the command was stopped, if it did not exit on its own
+	var terminated ResultStatus
+	if err != nil {
+		switch {
+		case ctx.Err() != nil:
+			terminated = StatusCancelled
+			result.Error = "command was cancelled"
+		case cmdCtx.Err() == context.DeadlineExceeded:
+			terminated = StatusTimedOut
+			result.Error = fmt.Sprintf("command timed out after %s", timeout)
+		}
 	}
 	
-	// For lint commands, try to extract file count
-	if command.Type == LintCheck {
-		result.FileCount = r.extractFileCount(result.Output)
+	// Parse output based on command type; parsers only extract counts,
+	// the success criteria decide whether the command passed
+	var parseErr error
+	if format := r.parser.Format(command.Type); format != "" {
+		var diagnostics []Diagnostic
+		diagnostics, parseErr = r.parser.ParseFormat(format, result.Output)
+		result.setDiagnostics(diagnostics)
+		result.IssueCount = len(diagnostics)
+	} else if patterns := r.parser.PatternParser(command.Type); patterns != nil {
+		diagnostics, issueCount := patterns.Parse(result.Output)
+		result.setDiagnostics(diagnostics)
+		result.IssueCount = issueCount
 	} else {
-		result.FileCount = 0
+		r.parseOutput(command.Type, result.Output, &result)
 	}
+	
+	// Test reports written by this run replace counts scraped from the output
+	if len(command.Reports) > 0 && terminated == "" {
+		cases, found, reportErr := r.readReports(command, reports)
+		parseErr = errors.Join(parseErr, reportErr)
+		if found {
+			testResult := testResultFromCases(cases)
+			result.TestCases = cases
+			result.IssueCount = testResult.FailedTests
+			result.TotalTests = testResult.TotalTests
+			result.PassedTests = testResult.PassedTests
+			result.FailedTests = testResult.FailedTests
+			result.SkippedTests = testResult.SkippedTests
+		}
+	}
+	
+	// Coverage written by this run, compared with the previous run's
+	var coverageFailure string
+	if command.Coverage.Report != "" && terminated == "" {
+		coverage, err := r.readCoverage(command.Coverage, reports)
+		parseErr = errors.Join(parseErr, err)
+		if coverage != nil {
+			if previous := r.history.latestCoverage(command.Type); previous != nil {
+				coverage.Delta = coverageDelta(coverage, previous)
+			}
+			result.Coverage = coverage
+		}
+		coverageFailure = checkCoverage(command.Coverage, coverage)
+	}
+	
+	passed, reason := command.Success.Evaluate(result.ExitCode, result.Output)
+	if passed && coverageFailure != "" {
+		passed, reason = false, coverageFailure
+	}
+	result.Passed = passed
+	switch {
+	case passed:
+		// An accepted non-zero exit code is not an error
+		result.Error = ""
+	case result.Error == "":
+		result.Error = reason
+	}
+	if parseErr != nil && result.Error == "" {
+		result.Error = parseErr.Error()
+	}
+	result.Status = statusFromPassed(result.Passed)
+	if !passed && coverageFailure == "" && r.flaky.Unstable && len(result.Diagnostics) == 0 &&
+		onlyFlakyFailures(result, r.history.Flakiness(), r.flaky.MinScore) {
+		result.Status = StatusUnstable
+	}
+	if !passed && exceeded != "" {
+		result.LimitExceeded = exceeded
+		result.Status = StatusResourceExceeded
+		result.Error = command.Limits.limitError(exceeded)
+	}
+	if terminated != "" {
+		result.Passed = false
+		result.Status = terminated
+	}
+	return result
+}
 
-	// Add to history
-	r.history.Add(result)
+// cacheKey returns the result cache key for a command, or an empty string
+// if the command cannot be cached
+func (r *Runner) cacheKey(command Command) string {
+	if r.cache == nil || command.Incremental() {
+		return ""
+	}
+	// Hash the timeout the command actually runs with
+	if command.Timeout == 0 {
+		command.Timeout = r.config.DefaultTimeout
+	}
+	key, err := CacheKey(r.config.WorkingDir, command, r.parser.definition(command.Type))
+	if err != nil {
+		return ""
+	}
+	return key
+}
+
+// killGracePeriod returns how long terminated commands get before being killed
+func (r *Runner) killGracePeriod() time.Duration {
+	if r.config.KillGracePeriod > 0 {
+		return r.config.KillGracePeriod
+	}
+	return defaultKillGracePeriod
+}
+
+// RestartCommand cancels any in-flight run of the command's type and runs
+// it again. The cancelled runs return results marked as superseded.
+func (r *Runner) RestartCommand(ctx context.Context, command Command) CommandResult {
+	if command.Type != GitHubActions {
+		r.tracker.supersede(command.Type)
+	}
+	return r.RunCommand(ctx, command)
+}
 
+// recordResult links a result to the command's run and adds it to history
+// unless a newer run of the same command has superseded it, in which case
+// it is marked and discarded
+func (r *Runner) recordResult(command Command, result CommandResult) CommandResult {
+	result.RunRef = command.RunRef
+	if !r.tracker.record(command.Type, result.Generation) {
+		result.Superseded = true
+		return result
+	}
+	
+	r.addResult(result)
 	return result
 }
 
@@ -107,10 +394,12 @@ func (r *Runner) RunCommand(ctx context.Context, command Command) CommandResult
 func (r *Runner) runGitHubCommand(ctx context.Context, command Command) CommandResult {
 	if r.githubClient == nil {
 		return CommandResult{
+			Type:      command.Type,
 			Command:   command.Command,
 			Timestamp: time.Now(),
 			Error:     "GitHub client not initialized - no GitHub repository detected or token missing",
 			Duration:  0,
+			RunRef:    command.RunRef,
 		}
 	}
 	
@@ -118,26 +407,84 @@ func (r *Runner) runGitHubCommand(ctx context.Context, command Command) CommandR
 	if err != nil {
 		result.Error = err.Error()
 	}
+	result.Type = command.Type
+	result.Status = statusFromPassed(result.Passed)
+	result.RunRef = command.RunRef
 	
 	// Add to history
-	r.history.Add(result)
+	r.addResult(result)
 	
 	return result
 }
 
-// RunAll executes all configured commands
-func (r *Runner) RunAll(ctx context.Context) map[CommandType]CommandResult {
-	commands := r.getDefaultCommands()
+// RunAll executes all configured commands as one run, starting each one
+// only after the commands it depends on have passed
+func (r *Runner) RunAll(ctx context.Context, trigger Trigger) RunResult {
+	return r.runCommands(ctx, r.StartRun(trigger, nil), r.getDefaultCommands(), false)
+}
+
+// RunOne executes a single command as a run of its own
+func (r *Runner) RunOne(ctx context.Context, trigger Trigger, command Command) RunResult {
+	commands := map[CommandType]Command{command.Type: command}
+	return r.runCommands(ctx, r.StartRun(trigger, nil), commands, false)
+}
+
+// RunChanged restarts commands as one run started by changes to files,
+// superseding any runs of them that are still in flight
+func (r *Runner) RunChanged(ctx context.Context, changedFiles []string, commands []Command) RunResult {
+	byType := make(map[CommandType]Command, len(commands))
+	for _, command := range commands {
+		byType[command.Type] = command
+	}
+	return r.runCommands(ctx, r.StartRun(TriggerFileChange, changedFiles), byType, true)
+}
+
+// runCommands executes commands as part of a run, starting each one only
+// after the commands it depends on have passed. Dependencies that are not
+// among the commands are ignored.
+func (r *Runner) runCommands(ctx context.Context, run RunResult, commands map[CommandType]Command, restart bool) RunResult {
 	results := make(map[CommandType]CommandResult)
 	
+	// Each command closes its channel once its result is recorded
+	done := make(map[CommandType]chan struct{}, len(commands))
+	for cmdType := range commands {
+		done[cmdType] = make(chan struct{})
+	}
+	
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	
 	for cmdType, cmd := range commands {
+		cmd.RunRef = run.ID
 		wg.Add(1)
 		go func(ct CommandType, c Command) {
 			defer wg.Done()
-			result := r.RunCommand(ctx, c)
+			defer close(done[ct])
+			
+			// Wait for upstream commands; disabled dependencies are ignored
+			for _, dep := range c.DependsOn {
+				if depDone, exists := done[dep]; exists {
+					select {
+					case <-depDone:
+					case <-ctx.Done():
+					}
+				}
+			}
+			
+			mu.Lock()
+			blocker := failedDependency(c, results, done)
+			mu.Unlock()
+			
+			var result CommandResult
+			switch {
+			case blocker != "":
+				result = r.skipCommand(c, blocker)
+			case restart:
+				result = r.RestartCommand(ctx, c)
+			default:
+				result = r.RunCommand(ctx, c)
+			}
+			
 			mu.Lock()
 			results[ct] = result
 			mu.Unlock()
@@ -145,7 +492,49 @@ func (r *Runner) RunAll(ctx context.Context) map[CommandType]CommandResult {
 	}
 	
 	wg.Wait()
-	return results
+	run.Commands = results
+	run.Duration = time.Since(run.Timestamp)
+	return run
+}
+
+// failedDependency returns the first enabled dependency that did not pass
+func failedDependency(command Command, results map[CommandType]CommandResult, done map[CommandType]chan struct{}) CommandType {
+	for _, dep := range command.DependsOn {
+		if _, enabled := done[dep]; !enabled {
+			continue
+		}
+		if result, exists := results[dep]; !exists || !result.State().Succeeded() {
+			return dep
+		}
+	}
+	return ""
+}
+
+// skipCommand records a command that was not run because a dependency failed
+func (r *Runner) skipCommand(command Command, blocker CommandType) CommandResult {
+	result := CommandResult{
+		Type:      command.Type,
+		Command:   command.Command,
+		Status:    StatusSkipped,
+		Timestamp: time.Now(),
+		Error:     fmt.Sprintf("skipped: dependency %s did not pass", blocker),
+		RunRef:    command.RunRef,
+	}
+	
+	r.addResult(result)
+	
+	return result
+}
+
+// Trends computes the trend of every command in the history
+func (r *Runner) Trends() []Trend {
+	return r.history.Trends(r.trends)
+}
+
+// ConfiguredCommand returns the configured command for a command type
+func (r *Runner) ConfiguredCommand(cmdType CommandType) (Command, bool) {
+	command, exists := r.getDefaultCommands()[cmdType]
+	return command, exists
 }
 
 // GetLatestResults returns the latest results for each command type
@@ -158,67 +547,30 @@ func (r *Runner) GetHistory() []CommandResult {
 	return r.history.GetAll()
 }
 
+// History returns the runner's result history
+func (r *Runner) History() *ResultHistory {
+	return r.history
+}
+
 // ClearHistory clears the command history
 func (r *Runner) ClearHistory() {
 	r.history.Clear()
 }
 
-// getDefaultCommands returns the configured commands to run
+// getDefaultCommands returns the configured commands to run, falling back
+// to the default configuration
 func (r *Runner) getDefaultCommands() map[CommandType]Command {
 	commands := make(map[CommandType]Command)
 	
-	// Use kwatch config if available, otherwise fall back to hardcoded defaults
-	if r.kwatchConfig != nil {
-		enabledCommands := r.kwatchConfig.GetEnabledCommands()
-		
-		for name, configCmd := range enabledCommands {
-			var cmdType CommandType
-			switch name {
-			case "typescript":
-				cmdType = TypescriptCheck
-			case "lint":
-				cmdType = LintCheck
-			case "test":
-				cmdType = TestRunner
-			case "github_actions":
-				cmdType = GitHubActions
-			default:
-				// For custom commands, use the name as the type
-				cmdType = CommandType(name)
-			}
-			
-			// Get timeout for this command
-			timeout := r.kwatchConfig.GetTimeout(name)
-			
-			commands[cmdType] = Command{
-				Type:    cmdType,
-				Command: configCmd.Command,
-				Args:    configCmd.Args,
-				Timeout: timeout,
-			}
-		}
-	} else {
-		// Fallback to hardcoded defaults
-		commands = map[CommandType]Command{
-			TypescriptCheck: {
-				Type:    TypescriptCheck,
-				Command: "npx",
-				Args:    []string{"tsc", "--noEmit"},
-				Timeout: 30 * time.Second,
-			},
-			LintCheck: {
-				Type:    LintCheck,
-				Command: "npx",
-				Args:    []string{"eslint", ".", "--ext", ".ts,.tsx,.js,.jsx"},
-				Timeout: 30 * time.Second,
-			},
-			TestRunner: {
-				Type:    TestRunner,
-				Command: "npm",
-				Args:    []string{"test"},
-				Timeout: 60 * time.Second,
-			},
-		}
+	kwatchConfig := r.kwatchConfig
+	if kwatchConfig == nil {
+		kwatchConfig = config.DefaultConfig()
+	}
+	
+	// Command names and types share the same spelling
+	for name, configCmd := range kwatchConfig.GetEnabledCommands() {
+		cmdType := CommandType(name)
+		commands[cmdType] = commandFromConfig(cmdType, configCmd, kwatchConfig.GetTimeout(name))
 	}
 	
 	// Always add GitHub Actions if client is available
@@ -235,35 +587,97 @@ func (r *Runner) getDefaultCommands() map[CommandType]Command {
 }
 
 
+// commandFromConfig builds the command of a type from its configuration
+func commandFromConfig(cmdType CommandType, configCmd config.Command, timeout time.Duration) Command {
+	// Unset backoff leaves the default
+	retryBackoff, _ := time.ParseDuration(configCmd.RetryBackoff)
+	
+	// Unset limits are unlimited
+	memoryLimit, _ := config.ParseSize(configCmd.Limits.Memory)
+	cpuTimeLimit, _ := time.ParseDuration(configCmd.Limits.CPUTime)
+	
+	var dependsOn []CommandType
+	for _, dep := range configCmd.DependsOn {
+		dependsOn = append(dependsOn, CommandType(dep))
+	}
+	
+	return Command{
+		Type:      cmdType,
+		Command:   configCmd.Command,
+		Args:      configCmd.Args,
+		Timeout:   timeout,
+		Weight:    configCmd.Weight,
+		Priority:  configCmd.Priority,
+		DependsOn: dependsOn,
+		Inputs:    configCmd.Inputs,
+		IncrementalArgs:  configCmd.IncrementalArgs,
+		IncrementalFiles: configCmd.IncrementalFiles,
+		Reports:          configCmd.Reports,
+		Coverage: CoverageSettings{
+			Report:       configCmd.Coverage.Report,
+			Format:       configCmd.Coverage.Format,
+			MinLines:     configCmd.Coverage.MinCoverage.Lines,
+			MinBranches:  configCmd.Coverage.MinCoverage.Branches,
+			MinFunctions: configCmd.Coverage.MinCoverage.Functions,
+		},
+		Success: SuccessCriteria{
+			ExitCodes: configCmd.Success.ExitCodes,
+			Require:   compilePatterns(configCmd.Success.Require),
+			Forbid:    compilePatterns(configCmd.Success.Forbid),
+		},
+		Retry: RetryPolicy{
+			Retries:   configCmd.Retries,
+			ExitCodes: configCmd.RetryOn.ExitCodes,
+			Output:    compilePatterns(configCmd.RetryOn.Output),
+			Backoff:   retryBackoff,
+		},
+		Limits: ResourceLimits{
+			Memory:    memoryLimit,
+			CPUTime:   cpuTimeLimit,
+			OpenFiles: configCmd.Limits.OpenFiles,
+			Processes: configCmd.Limits.Processes,
+		},
+	}
+}
+
+// compilePatterns compiles the regular expressions of a command's
+// configuration. Config validation has already rejected invalid ones.
+func compilePatterns(patterns []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		if re, err := regexp.Compile(pattern); err == nil {
+			compiled = append(compiled, re)
+		}
+	}
+	return compiled
+}
+
 // FormatCompactStatus formats results as a compact one-line status
 func FormatCompactStatus(results map[CommandType]CommandResult) string {
 	var parts []string
 	
-	// Order: TSC, LINT, TEST, GITHUB
-	types := []CommandType{TypescriptCheck, LintCheck, TestRunner, GitHubActions}
-	labels := map[CommandType]string{
-		TypescriptCheck: "TSC",
-		LintCheck:       "LINT",
-		TestRunner:      "TEST",
-		GitHubActions:   "GH",
+	// Registered checkers in registration order, then custom commands
+	types := make([]CommandType, 0, len(results))
+	for cmdType := range results {
+		types = append(types, cmdType)
 	}
+	SortCommandTypes(types)
 	
 	for _, cmdType := range types {
 		if result, exists := results[cmdType]; exists {
-			symbol := "✓"
-			if !result.Passed {
-				symbol = "✗"
-			}
+			symbol := StatusSymbol(result.State())
+			label := cmdType.Symbol()
 			
-			if cmdType == TestRunner {
+			switch cmdType.Kind() {
+			case KindTest:
 				// For tests, show PASS/TOTAL format
 				if result.TotalTests > 0 {
-					parts = append(parts, fmt.Sprintf("%s:%s%d/%d", labels[cmdType], symbol, result.PassedTests, result.TotalTests))
+					parts = append(parts, fmt.Sprintf("%s:%s%d/%d", label, symbol, result.PassedTests, result.TotalTests))
 				} else {
-					parts = append(parts, fmt.Sprintf("%s:%s%d", labels[cmdType], symbol, result.IssueCount))
+					parts = append(parts, fmt.Sprintf("%s:%s%d", label, symbol, result.IssueCount))
 				}
-			} else if cmdType == GitHubActions {
-				// For GitHub Actions, show number of failed jobs
+			case KindCI:
+				// For CI, show number of failed jobs
 				if len(result.JobResults) > 0 {
 					failedJobs := 0
 					for _, job := range result.JobResults {
@@ -271,14 +685,16 @@ func FormatCompactStatus(results map[CommandType]CommandResult) string {
 							failedJobs++
 						}
 					}
-					parts = append(parts, fmt.Sprintf("%s:%s%d/%d", labels[cmdType], symbol, failedJobs, len(result.JobResults)))
+					parts = append(parts, fmt.Sprintf("%s:%s%d/%d", label, symbol, failedJobs, len(result.JobResults)))
 				} else {
-					parts = append(parts, fmt.Sprintf("%s:%s", labels[cmdType], symbol))
+					parts = append(parts, fmt.Sprintf("%s:%s", label, symbol))
+				}
+			default:
+				if result.IssueCount > 0 && result.FileCount > 0 {
+					parts = append(parts, fmt.Sprintf("%s:%s%d/%d", label, symbol, result.IssueCount, result.FileCount))
+				} else {
+					parts = append(parts, fmt.Sprintf("%s:%s%d", label, symbol, result.IssueCount))
 				}
-			} else if result.IssueCount > 0 && result.FileCount > 0 {
-				parts = append(parts, fmt.Sprintf("%s:%s%d/%d", labels[cmdType], symbol, result.IssueCount, result.FileCount))
-			} else {
-				parts = append(parts, fmt.Sprintf("%s:%s%d", labels[cmdType], symbol, result.IssueCount))
 			}
 		}
 	}
@@ -286,37 +702,22 @@ func FormatCompactStatus(results map[CommandType]CommandResult) string {
 	return strings.Join(parts, " ")
 }
 
-// parseCommandOutput parses command output based on command type
-func (r *Runner) parseCommandOutput(cmdType CommandType, output string) (bool, int) {
-	switch cmdType {
-	case TypescriptCheck:
-		return r.parser.ParseTypeScriptOutput(output)
-	case LintCheck:
-		return r.parser.ParseLintOutput(output)
+// StatusSymbol returns the compact symbol for a result status
+func StatusSymbol(status ResultStatus) string {
+	switch status {
+	case StatusPassed:
+		return "✓"
+	case StatusSkipped:
+		return "⊘"
+	case StatusTimedOut:
+		return "◷"
+	case StatusCancelled:
+		return "⊗"
+	case StatusUnstable:
+		return "≈"
+	case StatusResourceExceeded:
+		return "⊠"
 	default:
-		return r.parser.ParseGenericOutput(output)
+		return "✗"
 	}
 }
-
-// extractFileCount extracts the number of files with issues from ESLint output
-func (r *Runner) extractFileCount(output string) int {
-	// Count unique file paths in ESLint output
-	lines := strings.Split(output, "\n")
-	fileMap := make(map[string]bool)
-	
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		// ESLint file paths start with / or ./ and contain .ts, .js, etc.
-		if (strings.HasPrefix(line, "/") || strings.HasPrefix(line, "./")) && 
-		   (strings.Contains(line, ".ts") || strings.Contains(line, ".js") || 
-		    strings.Contains(line, ".tsx") || strings.Contains(line, ".jsx")) {
-			// Extract just the file path (before any spaces/colons)
-			parts := strings.Fields(line)
-			if len(parts) > 0 {
-				fileMap[parts[0]] = true
-			}
-		}
-	}
-	
-	return len(fileMap)
-}
\ No newline at end of file
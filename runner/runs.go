@@ -0,0 +1,259 @@
+package runner
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Trigger is the reason a run started
+type Trigger string
+
+const (
+	TriggerFileChange Trigger = "file_change"
+	TriggerManual     Trigger = "manual"
+	TriggerAPI        Trigger = "api"
+	TriggerSchedule   Trigger = "schedule"
+	TriggerMCP        Trigger = "mcp"
+)
+
+// RunResult groups the results of the commands started together by one
+// trigger. Results link back to it through CommandResult.RunRef.
+type RunResult struct {
+	ID      string  `json:"id"`
+	Trigger Trigger `json:"trigger"`
+	// ChangedFiles are the files whose change started the run
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	// GitSHA is the commit checked out when the run started, and Dirty
+	// whether the working tree had uncommitted changes
+	GitSHA    string    `json:"git_sha,omitempty"`
+	Dirty     bool      `json:"dirty,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// Duration and Commands are filled in from the run's results; they are
+	// not stored with the run
+	Duration time.Duration                 `json:"duration,omitempty"`
+	Commands map[CommandType]CommandResult `json:"commands,omitempty"`
+}
+
+// Passed reports whether every command of the run succeeded
+func (r RunResult) Passed() bool {
+	for _, result := range r.Commands {
+		if !result.State().Succeeded() {
+			return false
+		}
+	}
+	return true
+}
+
+// newRunID returns a unique run ID that sorts by start time
+func newRunID(start time.Time) string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return start.UTC().Format("20060102T150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// StartRun records the start of a run. Commands run as part of it must
+// have their RunRef set to the run's ID.
+func (r *Runner) StartRun(trigger Trigger, changedFiles []string) RunResult {
+	run := RunResult{
+		Trigger:      trigger,
+		ChangedFiles: changedFiles,
+		Timestamp:    time.Now(),
+	}
+	run.ID = newRunID(run.Timestamp)
+
+	if r.config.WorkingDir != "" {
+		if sha, dirty, err := GitState(r.config.WorkingDir); err == nil {
+			run.GitSHA, run.Dirty = sha, dirty
+		}
+	}
+
+	r.history.AddRun(run)
+	return run
+}
+
+// Runs returns every recorded run with its results, newest first
+func (r *Runner) Runs() []RunResult {
+	return r.history.Runs()
+}
+
+// Run returns the recorded run with the given ID
+func (r *Runner) Run(id string) (RunResult, bool) {
+	for _, run := range r.history.Runs() {
+		if run.ID == id {
+			return run, true
+		}
+	}
+	return RunResult{}, false
+}
+
+// AddRun records a run. Runs are persisted in the history store when
+// there is one.
+func (h *ResultHistory) AddRun(run RunResult) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	run.Commands, run.Duration = nil, 0
+	if h.store != nil && h.store.AppendRun(run) == nil {
+		return
+	}
+	h.runs = append(h.runs, run)
+}
+
+// Runs returns the recorded runs with their results, newest first
+func (h *ResultHistory) Runs() []RunResult {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	var runs []RunResult
+	if h.store != nil {
+		if stored, err := h.store.LoadRuns(); err == nil {
+			runs = stored
+		}
+	}
+	runs = append(runs, h.runs...)
+
+	return attachRunResults(runs, h.all())
+}
+
+// attachRunResults fills in the commands and duration of each run from
+// the results that link to it, and orders the runs newest first
+func attachRunResults(runs []RunResult, results []CommandResult) []RunResult {
+	byID := make(map[string]*RunResult, len(runs))
+	for i := range runs {
+		runs[i].Commands = make(map[CommandType]CommandResult)
+		byID[runs[i].ID] = &runs[i]
+	}
+
+	for _, result := range results {
+		run := byID[result.RunRef]
+		if run == nil {
+			continue
+		}
+		run.Commands[result.CommandType()] = result
+		if end := result.Timestamp.Add(result.Duration).Sub(run.Timestamp); end > run.Duration {
+			run.Duration = end
+		}
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].Timestamp.After(runs[j].Timestamp)
+	})
+	return runs
+}
+
+// runsFile is the file in a history store that runs are appended to
+const runsFile = "runs.jsonl"
+
+// AppendRun writes a run to the store
+func (s *HistoryStore) AppendRun(run RunResult) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	line, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("failed to encode run: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Join(s.dir, runsFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open runs file: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write run: %w", err)
+	}
+	return file.Close()
+}
+
+// LoadRuns returns every stored run in the order it was started
+func (s *HistoryStore) LoadRuns() ([]RunResult, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.readRuns()
+}
+
+// readRuns reads the runs file, skipping lines that cannot be parsed
+func (s *HistoryStore) readRuns() ([]RunResult, error) {
+	file, err := os.Open(filepath.Join(s.dir, runsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open runs file: %w", err)
+	}
+	defer file.Close()
+
+	var runs []RunResult
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var run RunResult
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read runs file: %w", err)
+	}
+	return runs, nil
+}
+
+// pruneRuns drops the runs none of the kept results link to that started
+// before the oldest kept result. The caller must hold the store's locks.
+func (s *HistoryStore) pruneRuns(kept []CommandResult) error {
+	runs, err := s.readRuns()
+	if err != nil || len(runs) == 0 {
+		return err
+	}
+
+	referenced := make(map[string]bool)
+	var oldest time.Time
+	for _, result := range kept {
+		referenced[result.RunRef] = true
+		if oldest.IsZero() || result.Timestamp.Before(oldest) {
+			oldest = result.Timestamp
+		}
+	}
+
+	var data []byte
+	removed := 0
+	for _, run := range runs {
+		if !referenced[run.ID] && (oldest.IsZero() || run.Timestamp.Before(oldest)) {
+			removed++
+			continue
+		}
+		line, err := json.Marshal(run)
+		if err != nil {
+			return fmt.Errorf("failed to encode run: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if removed == 0 {
+		return nil
+	}
+
+	path := filepath.Join(s.dir, runsFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write runs file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write runs file: %w", err)
+	}
+	return nil
+}
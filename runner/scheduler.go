@@ -0,0 +1,156 @@
+package runner
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Scheduler bounds how many command slots are in use at once.
+// Each command claims a number of slots equal to its weight. Waiting
+// commands are started by priority (highest first), then by weight
+// (lightest first), then in the order they were queued.
+type Scheduler struct {
+	capacity int
+	used     int
+	waiters  []*schedulerWaiter
+	sequence uint64
+	mutex    sync.Mutex
+}
+
+// schedulerWaiter is a command waiting for slots to become free
+type schedulerWaiter struct {
+	weight   int
+	priority int
+	sequence uint64
+	granted  bool
+	ready    chan struct{}
+}
+
+// NewScheduler creates a scheduler with the given number of slots
+func NewScheduler(capacity int) *Scheduler {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Scheduler{capacity: capacity}
+}
+
+// Capacity returns the total number of slots
+func (s *Scheduler) Capacity() int {
+	return s.capacity
+}
+
+// Acquire blocks until enough slots are free for a command of the given
+// weight, or the context is done. It returns the number of slots claimed,
+// which must be passed to Release once the command has finished.
+func (s *Scheduler) Acquire(ctx context.Context, weight, priority int) (int, error) {
+	weight = s.normalizeWeight(weight)
+
+	s.mutex.Lock()
+	if len(s.waiters) == 0 && s.used+weight <= s.capacity {
+		s.used += weight
+		s.mutex.Unlock()
+		return weight, nil
+	}
+
+	s.sequence++
+	w := &schedulerWaiter{
+		weight:   weight,
+		priority: priority,
+		sequence: s.sequence,
+		ready:    make(chan struct{}),
+	}
+	s.enqueue(w)
+	s.mutex.Unlock()
+
+	select {
+	case <-w.ready:
+		return weight, nil
+	case <-ctx.Done():
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		// The slots may have been granted while we were giving up
+		if w.granted {
+			s.used -= weight
+		} else {
+			s.remove(w)
+		}
+		s.dispatch()
+		return 0, ctx.Err()
+	}
+}
+
+// Release returns slots claimed by Acquire and starts queued commands
+func (s *Scheduler) Release(weight int) {
+	if weight <= 0 {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.used -= weight
+	if s.used < 0 {
+		s.used = 0
+	}
+	s.dispatch()
+}
+
+// normalizeWeight clamps a weight to the range [1, capacity] so a heavy
+// command can never wait forever for more slots than exist
+func (s *Scheduler) normalizeWeight(weight int) int {
+	if weight < 1 {
+		return 1
+	}
+	if weight > s.capacity {
+		return s.capacity
+	}
+	return weight
+}
+
+// enqueue inserts a waiter keeping the queue in scheduling order
+func (s *Scheduler) enqueue(w *schedulerWaiter) {
+	index := sort.Search(len(s.waiters), func(i int) bool {
+		return waitsBefore(w, s.waiters[i])
+	})
+	s.waiters = append(s.waiters, nil)
+	copy(s.waiters[index+1:], s.waiters[index:])
+	s.waiters[index] = w
+}
+
+// remove drops a waiter from the queue
+func (s *Scheduler) remove(w *schedulerWaiter) {
+	for i, queued := range s.waiters {
+		if queued == w {
+			s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// dispatch grants slots to queued commands in order. The head of the
+// queue is never overtaken, so heavy commands are not starved.
+func (s *Scheduler) dispatch() {
+	for len(s.waiters) > 0 {
+		head := s.waiters[0]
+		if s.used+head.weight > s.capacity {
+			return
+		}
+		s.used += head.weight
+		head.granted = true
+		close(head.ready)
+		s.waiters = s.waiters[1:]
+	}
+}
+
+// waitsBefore reports whether waiter a should be started before waiter b
+func waitsBefore(a, b *schedulerWaiter) bool {
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	if a.weight != b.weight {
+		return a.weight < b.weight
+	}
+	return a.sequence < b.sequence
+}
@@ -0,0 +1,471 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxSegmentEntries is the number of results written to a history
+// segment before a new one is started
+const maxSegmentEntries = 1000
+
+// HistoryStore persists command results as JSONL segments in a directory,
+// normally .kwatch/history/. An index lists the segments with their entry
+// counts and time ranges, and outputs are stored once per distinct content
+// under objects/. Several processes (the TUI, the daemon, the MCP server
+// and one-shot commands) can share a store: writes take a file lock, and
+// reads pick up entries appended by other processes.
+type HistoryStore struct {
+	dir      string
+	policy   RetentionPolicy
+	readOnly bool
+
+	// Results read so far with the positions of each command's results in
+	// them, how many entries were read from each segment, and the outputs
+	// of the results read
+	cache     []CommandResult
+	byCommand map[CommandType][]int
+	latest    map[CommandType]int
+	loaded    map[string]historySegment
+	outputs   map[string]string
+	mutex     sync.Mutex
+}
+
+// historyIndex is the on-disk index of a history store
+type historyIndex struct {
+	Segments    []historySegment `json:"segments"`
+	NextSegment int              `json:"next_segment,omitempty"`
+	Compacted   time.Time        `json:"compacted,omitempty"`
+}
+
+// historySegment describes one JSONL segment file
+type historySegment struct {
+	Name  string    `json:"name"`
+	Count int       `json:"count"`
+	Bytes int64     `json:"bytes"`
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+}
+
+// HistoryDir returns the history directory for a project
+func HistoryDir(workingDir string) string {
+	return filepath.Join(workingDir, ".kwatch", "history")
+}
+
+// OpenHistoryStore opens the history store in dir. The directory is
+// created when the store is first written to.
+func OpenHistoryStore(dir string) (*HistoryStore, error) {
+	store := &HistoryStore{dir: dir}
+	store.resetCache()
+	return store, nil
+}
+
+// OpenHistoryStoreReadOnly opens the history store in dir for reading. A
+// missing directory reads as an empty store, and writes fail.
+func OpenHistoryStoreReadOnly(dir string) (*HistoryStore, error) {
+	store, err := OpenHistoryStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	store.readOnly = true
+	return store, nil
+}
+
+// SetRetention sets the policy enforced when the store is compacted.
+// Appends compact the store automatically once it is due.
+func (s *HistoryStore) SetRetention(policy RetentionPolicy) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.policy = policy
+}
+
+// Dir returns the directory the store writes to
+func (s *HistoryStore) Dir() string {
+	return s.dir
+}
+
+// lock creates the store's directory if needed and takes its file lock
+// for a write
+func (s *HistoryStore) lock() (unlock func(), err error) {
+	if s.readOnly {
+		return nil, fmt.Errorf("history store %s is read-only", s.dir)
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+	return lockDir(s.dir)
+}
+
+// Append writes a result to the newest segment, starting a new segment
+// when the current one is full
+func (s *HistoryStore) Append(result CommandResult) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entry, err := s.storeOutput(result)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+	line = append(line, '\n')
+
+	index, err := s.readIndex()
+	if err != nil {
+		return err
+	}
+
+	if len(index.Segments) == 0 || index.Segments[len(index.Segments)-1].Count >= maxSegmentEntries {
+		index.Segments = append(index.Segments, historySegment{
+			Name:  index.newSegmentName(),
+			First: result.Timestamp,
+		})
+	}
+	segment := &index.Segments[len(index.Segments)-1]
+
+	file, err := os.OpenFile(filepath.Join(s.dir, segment.Name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history segment: %w", err)
+	}
+	if _, err := file.Write(line); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write history segment: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write history segment: %w", err)
+	}
+
+	segment.Count++
+	segment.Bytes += int64(len(line))
+	if segment.First.IsZero() || result.Timestamp.Before(segment.First) {
+		segment.First = result.Timestamp
+	}
+	if result.Timestamp.After(segment.Last) {
+		segment.Last = result.Timestamp
+	}
+
+	if err := s.writeIndex(index); err != nil {
+		return err
+	}
+
+	// The result is stored; a failed compaction is retried on a later append
+	if now := time.Now(); s.policy.compactionDue(index, now) {
+		s.prune(s.policy, now)
+	}
+	return nil
+}
+
+// Load returns every stored result in the order it was written
+func (s *HistoryStore) Load() ([]CommandResult, error) {
+	return s.LoadSince(time.Time{})
+}
+
+// LoadSince returns the stored results recorded at or after since
+func (s *HistoryStore) LoadSince(since time.Time) ([]CommandResult, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	results := make([]CommandResult, 0, len(s.cache))
+	for _, result := range s.cache {
+		if !result.Timestamp.Before(since) {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// latestResults returns the newest stored result of each command
+func (s *HistoryStore) latestResults() (map[CommandType]CommandResult, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	latest := make(map[CommandType]CommandResult, len(s.latest))
+	for cmdType, position := range s.latest {
+		latest[cmdType] = s.cache[position]
+	}
+	return latest, nil
+}
+
+// commandTypes returns the commands with stored results
+func (s *HistoryStore) commandTypes() ([]CommandType, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	types := make([]CommandType, 0, len(s.byCommand))
+	for cmdType := range s.byCommand {
+		types = append(types, cmdType)
+	}
+	return types, nil
+}
+
+// commandResults returns up to limit of the newest stored results of a
+// command that keep accepts, oldest first; see newestResults
+func (s *HistoryStore) commandResults(cmdType CommandType, limit int, keep func(CommandResult) bool) ([]CommandResult, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	positions := s.byCommand[cmdType]
+	return newestResults(len(positions), func(i int) CommandResult {
+		return s.cache[positions[i]]
+	}, limit, keep), nil
+}
+
+// recentResults returns up to limit of the newest stored results,
+// oldest first; see newestResults
+func (s *HistoryStore) recentResults(limit int, keep func(CommandResult) bool) ([]CommandResult, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.refresh(); err != nil {
+		return nil, err
+	}
+
+	return newestResults(len(s.cache), func(i int) CommandResult {
+		return s.cache[i]
+	}, limit, keep), nil
+}
+
+// newestResults returns up to limit of the last of count results that
+// keep accepts, oldest first. A limit of 0 returns all of them and a nil
+// keep accepts every result.
+func newestResults(count int, at func(int) CommandResult, limit int, keep func(CommandResult) bool) []CommandResult {
+	var results []CommandResult
+	for i := count - 1; i >= 0 && (limit <= 0 || len(results) < limit); i-- {
+		if result := at(i); keep == nil || keep(result) {
+			results = append(results, result)
+		}
+	}
+	for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+		results[i], results[j] = results[j], results[i]
+	}
+	return results
+}
+
+// Clear removes every stored result
+func (s *HistoryStore) Clear() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	index, err := s.readIndex()
+	if err != nil {
+		return err
+	}
+	for _, segment := range index.Segments {
+		if err := os.Remove(filepath.Join(s.dir, segment.Name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove history segment: %w", err)
+		}
+	}
+
+	if err := os.RemoveAll(filepath.Join(s.dir, "objects")); err != nil {
+		return fmt.Errorf("failed to remove history outputs: %w", err)
+	}
+	if err := os.Remove(filepath.Join(s.dir, runsFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove runs: %w", err)
+	}
+
+	s.resetCache()
+	return s.writeIndex(historyIndex{})
+}
+
+// resetCache forgets every result read so far
+func (s *HistoryStore) resetCache() {
+	s.cache = nil
+	s.byCommand = make(map[CommandType][]int)
+	s.latest = make(map[CommandType]int)
+	s.loaded = make(map[string]historySegment)
+	s.outputs = make(map[string]string)
+}
+
+// cacheResult adds a result read from a segment to the cache and indexes
+// it by command
+func (s *HistoryStore) cacheResult(result CommandResult) {
+	position := len(s.cache)
+	s.cache = append(s.cache, result)
+
+	cmdType := result.CommandType()
+	s.byCommand[cmdType] = append(s.byCommand[cmdType], position)
+	if latest, exists := s.latest[cmdType]; !exists || !result.Timestamp.Before(s.cache[latest].Timestamp) {
+		s.latest[cmdType] = position
+	}
+}
+
+// refresh brings the cache up to date with the index, reading only the
+// entries appended since the last refresh. Segments that were rewritten
+// or removed by another process cause a full reload.
+func (s *HistoryStore) refresh() error {
+	index, err := s.readIndex()
+	if err != nil {
+		return err
+	}
+
+	segments := make(map[string]historySegment, len(index.Segments))
+	for _, segment := range index.Segments {
+		segments[segment.Name] = segment
+	}
+	for name, loaded := range s.loaded {
+		if segment, exists := segments[name]; !exists || segment.Count < loaded.Count {
+			// Outputs are dropped too, so those of pruned results go with them
+			s.resetCache()
+			break
+		}
+	}
+
+	for _, segment := range index.Segments {
+		loaded := s.loaded[segment.Name]
+		if loaded.Count >= segment.Count {
+			continue
+		}
+
+		results, entries, bytes, err := s.readSegment(segment.Name, loaded.Bytes, segment.Count-loaded.Count)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			s.attachOutput(&result)
+			s.cacheResult(result)
+		}
+		s.loaded[segment.Name] = historySegment{
+			Name:  segment.Name,
+			Count: loaded.Count + entries,
+			Bytes: loaded.Bytes + bytes,
+		}
+	}
+
+	return nil
+}
+
+// readSegment reads up to count entries from a segment starting at offset.
+// It returns the decoded results, the number of entries and bytes consumed.
+func (s *HistoryStore) readSegment(name string, offset int64, count int) ([]CommandResult, int, int64, error) {
+	file, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, 0, nil
+		}
+		return nil, 0, 0, fmt.Errorf("failed to open history segment: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to read history segment: %w", err)
+	}
+
+	var results []CommandResult
+	var entries int
+	var consumed int64
+	reader := bufio.NewReader(file)
+	for entries < count {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// A partial trailing line is still being written
+			break
+		}
+		entries++
+		consumed += int64(len(line))
+
+		var result CommandResult
+		if err := json.Unmarshal(line, &result); err != nil {
+			// Skip corrupt entries rather than losing the rest of the history
+			continue
+		}
+		results = append(results, result)
+	}
+
+	return results, entries, consumed, nil
+}
+
+// readIndex reads the index, returning an empty one if none exists yet
+func (s *HistoryStore) readIndex() (historyIndex, error) {
+	var index historyIndex
+
+	data, err := os.ReadFile(filepath.Join(s.dir, "index.json"))
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return index, fmt.Errorf("failed to read history index: %w", err)
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return index, fmt.Errorf("failed to parse history index: %w", err)
+	}
+	return index, nil
+}
+
+// writeIndex atomically replaces the index
+func (s *HistoryStore) writeIndex(index historyIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode history index: %w", err)
+	}
+
+	path := filepath.Join(s.dir, "index.json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write history index: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write history index: %w", err)
+	}
+	return nil
+}
+
+// newSegmentName returns a name no segment of the store has used before,
+// so readers never confuse a new segment with one they already read
+func (index *historyIndex) newSegmentName() string {
+	index.ensureNextSegment()
+	name := fmt.Sprintf("segment-%06d.jsonl", index.NextSegment)
+	index.NextSegment++
+	return name
+}
+
+// ensureNextSegment derives NextSegment from the segment names when the
+// index does not record it yet
+func (index *historyIndex) ensureNextSegment() {
+	if index.NextSegment > 0 {
+		return
+	}
+
+	index.NextSegment = 1
+	for _, segment := range index.Segments {
+		var number int
+		if _, err := fmt.Sscanf(segment.Name, "segment-%d.jsonl", &number); err == nil && number >= index.NextSegment {
+			index.NextSegment = number + 1
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package runner
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OutputLine is a single line of output produced by a running command
+type OutputLine struct {
+	Command    CommandType `json:"command"`
+	Generation uint64      `json:"generation"`
+	Text       string      `json:"text"`
+	Timestamp  time.Time   `json:"timestamp"`
+}
+
+// OutputHandler receives output lines as commands produce them.
+// Handlers are called synchronously from the running command and
+// should return quickly.
+type OutputHandler func(OutputLine)
+
+// subscriberSet holds the handlers registered for values of one kind
+type subscriberSet[T any] struct {
+	handlers map[int]func(T)
+	next     int
+	mutex    sync.RWMutex
+}
+
+// add registers a handler and returns a function that removes it
+func (s *subscriberSet[T]) add(handler func(T)) (remove func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.handlers == nil {
+		s.handlers = make(map[int]func(T))
+	}
+	id := s.next
+	s.next++
+	s.handlers[id] = handler
+
+	return func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		delete(s.handlers, id)
+	}
+}
+
+// publish sends a value to all handlers
+func (s *subscriberSet[T]) publish(value T) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, handler := range s.handlers {
+		handler(value)
+	}
+}
+
+// empty reports whether no handler is registered
+func (s *subscriberSet[T]) empty() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return len(s.handlers) == 0
+}
+
+// Subscribe registers a handler that receives every output line of every
+// command run by this runner. The returned function removes the handler.
+func (r *Runner) Subscribe(handler OutputHandler) (unsubscribe func()) {
+	return r.subscribers.add(handler)
+}
+
+// publish sends an output line to all subscribers
+func (r *Runner) publish(line OutputLine) {
+	r.subscribers.publish(line)
+}
+
+// lineWriter collects a command's combined output and calls emit for
+// every complete line written to it
+type lineWriter struct {
+	output  bytes.Buffer
+	partial []byte
+	emit    func(string)
+}
+
+// newLineWriter creates a line writer that reports lines to emit
+func newLineWriter(emit func(string)) *lineWriter {
+	return &lineWriter{emit: emit}
+}
+
+// Write implements io.Writer
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.output.Write(p)
+	w.partial = append(w.partial, p...)
+
+	for {
+		index := bytes.IndexByte(w.partial, '\n')
+		if index < 0 {
+			break
+		}
+		line := strings.TrimRight(string(w.partial[:index]), "\r")
+		w.partial = w.partial[index+1:]
+		w.emit(line)
+	}
+
+	return len(p), nil
+}
+
+// Flush emits any trailing output that did not end with a newline
+func (w *lineWriter) Flush() {
+	if len(w.partial) > 0 {
+		w.emit(strings.TrimRight(string(w.partial), "\r"))
+		w.partial = nil
+	}
+}
+
+// String returns everything written so far
+func (w *lineWriter) String() string {
+	return w.output.String()
+}
@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// SuccessCriteria decides whether a finished command passed. By default
+// the exit code is authoritative: a command passes when it exits with 0,
+// and its output is only parsed for counts and diagnostics.
+type SuccessCriteria struct {
+	// ExitCodes are the exit codes that count as success (default 0)
+	ExitCodes []int `json:"exit_codes,omitempty"`
+	// Require are regular expressions the output must all match
+	Require []*regexp.Regexp `json:"require,omitempty"`
+	// Forbid are regular expressions the output must not match
+	Forbid []*regexp.Regexp `json:"forbid,omitempty"`
+}
+
+// Evaluate checks an exit code and output against the criteria. It returns
+// whether the command passed and, if not, why. An exit code of -1 means the
+// command did not run to completion and always fails.
+func (s SuccessCriteria) Evaluate(exitCode int, output string) (bool, string) {
+	if exitCode < 0 {
+		return false, "command did not exit normally"
+	}
+
+	allowed := s.ExitCodes
+	if len(allowed) == 0 {
+		allowed = []int{0}
+	}
+	accepted := false
+	for _, code := range allowed {
+		if code == exitCode {
+			accepted = true
+			break
+		}
+	}
+	if !accepted {
+		return false, fmt.Sprintf("exit code %d is not a success exit code", exitCode)
+	}
+
+	for _, re := range s.Require {
+		if !re.MatchString(output) {
+			return false, fmt.Sprintf("output does not match required pattern %q", re)
+		}
+	}
+
+	for _, re := range s.Forbid {
+		if re.MatchString(output) {
+			return false, fmt.Sprintf("output matches forbidden pattern %q", re)
+		}
+	}
+
+	return true, ""
+}
@@ -0,0 +1,301 @@
+package runner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"kwatch/config"
+)
+
+// Defaults for trend analysis when the config does not set them
+const (
+	DefaultTrendWindow      = 20
+	DefaultTrendRecent      = 5
+	DefaultDurationIncrease = 40.0
+	DefaultIssueIncrease    = 1
+)
+
+// Metrics a regression can be reported for
+const (
+	MetricDuration = "duration"
+	MetricIssues   = "issues"
+)
+
+// TrendPolicy decides when a command's latest runs are a regression. Zero
+// values use the defaults.
+type TrendPolicy struct {
+	// Window is the number of earlier runs whose median is the baseline
+	Window int
+	// Recent is the number of latest runs whose median is compared to it
+	Recent int
+	// DurationIncrease is the percentage by which the recent median
+	// duration must exceed the baseline
+	DurationIncrease float64
+	// IssueIncrease is how many issues the recent median must add
+	IssueIncrease int
+}
+
+// TrendPolicyFromConfig builds a trend policy from the kwatch configuration
+func TrendPolicyFromConfig(kwatchConfig *config.Config) TrendPolicy {
+	return TrendPolicy{
+		Window:           kwatchConfig.Trends.Window,
+		Recent:           kwatchConfig.Trends.Recent,
+		DurationIncrease: kwatchConfig.Trends.DurationIncrease,
+		IssueIncrease:    kwatchConfig.Trends.IssueIncrease,
+	}
+}
+
+// withDefaults fills unset values with the defaults
+func (p TrendPolicy) withDefaults() TrendPolicy {
+	if p.Window <= 0 {
+		p.Window = DefaultTrendWindow
+	}
+	if p.Recent <= 0 {
+		p.Recent = DefaultTrendRecent
+	}
+	if p.DurationIncrease <= 0 {
+		p.DurationIncrease = DefaultDurationIncrease
+	}
+	if p.IssueIncrease <= 0 {
+		p.IssueIncrease = DefaultIssueIncrease
+	}
+	return p
+}
+
+// Regression describes a metric whose recent median rose above its baseline
+type Regression struct {
+	Command CommandType `json:"command"`
+	Metric  string      `json:"metric"`
+	// Baseline and Recent are medians, in seconds for durations
+	Baseline float64 `json:"baseline"`
+	Recent   float64 `json:"recent"`
+	// Change is a percentage for durations and a count for issues
+	Change float64 `json:"change"`
+}
+
+// String describes the regression, as in "test duration up 45% (12.1s → 17.5s)"
+func (r Regression) String() string {
+	if r.Metric == MetricDuration {
+		return fmt.Sprintf("%s duration up %.0f%% (%s → %s)", r.Command, r.Change,
+			formatSeconds(r.Baseline), formatSeconds(r.Recent))
+	}
+	return fmt.Sprintf("%s issues up by %g (%g → %g)", r.Command, r.Change, r.Baseline, r.Recent)
+}
+
+// formatSeconds formats a number of seconds as a rounded duration
+func formatSeconds(seconds float64) string {
+	duration := time.Duration(seconds * float64(time.Second))
+	if duration >= time.Second {
+		return duration.Round(100 * time.Millisecond).String()
+	}
+	return duration.Round(time.Millisecond).String()
+}
+
+// Trend summarizes how a command's duration and issue count developed
+// over its recent completed runs
+type Trend struct {
+	Command CommandType `json:"command"`
+	// Runs counts the runs analyzed, at most Window + Recent
+	Runs int `json:"runs"`
+	// Baseline values are medians of the runs before the recent ones;
+	// Recent values are medians of the latest runs
+	BaselineDuration time.Duration `json:"baseline_duration"`
+	RecentDuration   time.Duration `json:"recent_duration"`
+	// DurationChange is the change of the recent median in percent
+	DurationChange float64 `json:"duration_change"`
+	BaselineIssues float64 `json:"baseline_issues"`
+	RecentIssues   float64 `json:"recent_issues"`
+	// Durations and Issues are the analyzed runs, oldest first
+	Durations   []time.Duration `json:"durations"`
+	Issues      []int           `json:"issues"`
+	Regressions []Regression    `json:"regressions,omitempty"`
+}
+
+// Regressed reports whether the trend has a regression for the metric
+func (t Trend) Regressed(metric string) bool {
+	for _, regression := range t.Regressions {
+		if regression.Metric == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// countsForTrend reports whether a result describes a complete run of the
+// command. Cached, incremental and interrupted runs are left out, since
+// their durations and issue counts are not comparable.
+func countsForTrend(result CommandResult) bool {
+	if result.Cached || len(result.ChangedFiles) > 0 || result.Duration <= 0 {
+		return false
+	}
+	switch result.State() {
+	case StatusPassed, StatusFailed, StatusUnstable:
+		return true
+	default:
+		return false
+	}
+}
+
+// AnalyzeTrends computes the trend of every command in the results, which
+// are expected oldest first, in display order
+func AnalyzeTrends(results []CommandResult, policy TrendPolicy) []Trend {
+	runs := make(map[CommandType][]CommandResult)
+	for _, result := range results {
+		if countsForTrend(result) {
+			cmdType := result.CommandType()
+			runs[cmdType] = append(runs[cmdType], result)
+		}
+	}
+
+	cmdTypes := make([]CommandType, 0, len(runs))
+	for cmdType := range runs {
+		cmdTypes = append(cmdTypes, cmdType)
+	}
+	SortCommandTypes(cmdTypes)
+
+	trends := make([]Trend, 0, len(cmdTypes))
+	for _, cmdType := range cmdTypes {
+		trends = append(trends, policy.analyze(cmdType, runs[cmdType]))
+	}
+	return trends
+}
+
+// analyze computes the trend of one command's complete runs, oldest first
+func (p TrendPolicy) analyze(cmdType CommandType, runs []CommandResult) Trend {
+	p = p.withDefaults()
+	if len(runs) > p.Window+p.Recent {
+		runs = runs[len(runs)-p.Window-p.Recent:]
+	}
+
+	trend := Trend{Command: cmdType, Runs: len(runs)}
+	durations := make([]float64, len(runs))
+	issues := make([]float64, len(runs))
+	for i, run := range runs {
+		trend.Durations = append(trend.Durations, run.Duration)
+		trend.Issues = append(trend.Issues, run.IssueCount)
+		durations[i] = run.Duration.Seconds()
+		issues[i] = float64(run.IssueCount)
+	}
+
+	// Without as many earlier runs as recent ones there is no baseline
+	split := len(runs) - p.Recent
+	if split < p.Recent {
+		return trend
+	}
+
+	baselineDuration, recentDuration := median(durations[:split]), median(durations[split:])
+	trend.BaselineDuration = time.Duration(baselineDuration * float64(time.Second))
+	trend.RecentDuration = time.Duration(recentDuration * float64(time.Second))
+	trend.BaselineIssues, trend.RecentIssues = median(issues[:split]), median(issues[split:])
+
+	if baselineDuration > 0 {
+		trend.DurationChange = (recentDuration - baselineDuration) / baselineDuration * 100
+		if trend.DurationChange >= p.DurationIncrease {
+			trend.Regressions = append(trend.Regressions, Regression{
+				Command:  cmdType,
+				Metric:   MetricDuration,
+				Baseline: baselineDuration,
+				Recent:   recentDuration,
+				Change:   trend.DurationChange,
+			})
+		}
+	}
+	if change := trend.RecentIssues - trend.BaselineIssues; change >= float64(p.IssueIncrease) {
+		trend.Regressions = append(trend.Regressions, Regression{
+			Command:  cmdType,
+			Metric:   MetricIssues,
+			Baseline: trend.BaselineIssues,
+			Recent:   trend.RecentIssues,
+			Change:   change,
+		})
+	}
+	return trend
+}
+
+// median returns the median of the values, which must not be empty
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// Trends computes the trend of every command in the history; see AnalyzeTrends
+func (h *ResultHistory) Trends(policy TrendPolicy) []Trend {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	// Only each command's latest complete runs are analyzed
+	limit := policy.withDefaults()
+	var results []CommandResult
+	for _, cmdType := range h.commandTypes() {
+		results = append(results, h.commandResults(cmdType, limit.Window+limit.Recent, countsForTrend)...)
+	}
+	return AnalyzeTrends(results, policy)
+}
+
+// previousResults returns the latest result of a command, if any, and the
+// latest complete runs its trend is computed from, oldest first
+func (h *ResultHistory) previousResults(cmdType CommandType, policy TrendPolicy) (*CommandResult, []CommandResult) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	var previous *CommandResult
+	if latest := h.commandResults(cmdType, 1, nil); len(latest) > 0 {
+		previous = &latest[0]
+	}
+	limit := policy.withDefaults()
+	return previous, h.commandResults(cmdType, limit.Window+limit.Recent, countsForTrend)
+}
+
+// sparkBlocks are the bars of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws the values as a line of bars scaled between their
+// minimum and maximum, using at most width of the latest values
+func Sparkline(values []float64, width int) string {
+	if width > 0 && len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := values[0], values[0]
+	for _, value := range values {
+		low, high = min(low, value), max(high, value)
+	}
+
+	var line strings.Builder
+	for _, value := range values {
+		level := 0
+		if high > low {
+			level = int((value-low)/(high-low)*float64(len(sparkBlocks)-1) + 0.5)
+		}
+		line.WriteRune(sparkBlocks[level])
+	}
+	return line.String()
+}
+
+// DurationSparkline draws a trend's durations; see Sparkline
+func (t Trend) DurationSparkline(width int) string {
+	values := make([]float64, len(t.Durations))
+	for i, duration := range t.Durations {
+		values[i] = duration.Seconds()
+	}
+	return Sparkline(values, width)
+}
+
+// IssueSparkline draws a trend's issue counts; see Sparkline
+func (t Trend) IssueSparkline(width int) string {
+	values := make([]float64, len(t.Issues))
+	for i, count := range t.Issues {
+		values[i] = float64(count)
+	}
+	return Sparkline(values, width)
+}
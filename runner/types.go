@@ -1,25 +1,67 @@
 package runner
 
 import (
-	"strings"
 	"sync"
 	"time"
 )
 
 // CommandResult represents the result of a command execution
 type CommandResult struct {
+	// Type is the configured command that produced the result
+	Type       CommandType   `json:"type,omitempty"`
 	Command    string        `json:"command"`
 	Passed     bool          `json:"passed"`
+	Status     ResultStatus  `json:"status,omitempty"`
 	IssueCount int           `json:"issue_count"`
 	FileCount  int           `json:"file_count"`
 	Output     string        `json:"output"`
+	// OutputHash identifies the output in the history store's object store
+	OutputHash string        `json:"output_hash,omitempty"`
 	Duration   time.Duration `json:"duration"`
 	Timestamp  time.Time     `json:"timestamp"`
 	Error      string        `json:"error,omitempty"`
+	// ExitCode is the command's exit code, or -1 if it did not exit normally
+	ExitCode int `json:"exit_code,omitempty"`
+	// Diagnostics reported by the tool, when its output could be parsed,
+	// and how many of them are errors, warnings and automatically fixable
+	Diagnostics  []Diagnostic `json:"diagnostics,omitempty"`
+	ErrorCount   int          `json:"error_count,omitempty"`
+	WarningCount int          `json:"warning_count,omitempty"`
+	FixableCount int          `json:"fixable_count,omitempty"`
+	// Generation numbers runs of the same command; Superseded marks a result
+	// discarded because a newer run of the command replaced it
+	Generation uint64 `json:"generation,omitempty"`
+	Superseded bool   `json:"superseded,omitempty"`
+	// Cached marks a result reused from an earlier run with identical inputs
+	Cached bool `json:"cached,omitempty"`
+	// RunRef is the ID of the run the result belongs to; see RunResult
+	RunRef string `json:"run_ref,omitempty"`
+	// ChangedFiles lists the files an incremental run checked; its
+	// diagnostics are merged into the last full run's
+	ChangedFiles []string `json:"changed_files,omitempty"`
 	// Test-specific fields
 	TotalTests   int `json:"total_tests,omitempty"`
 	PassedTests  int `json:"passed_tests,omitempty"`
 	FailedTests  int `json:"failed_tests,omitempty"`
+	SkippedTests int `json:"skipped_tests,omitempty"`
+	// TestCases are the individual tests read from the command's reports
+	TestCases []TestCase `json:"test_cases,omitempty"`
+	// Packages are the per-package outcomes of a go test run
+	Packages []TestPackage `json:"packages,omitempty"`
+	// Coverage is read from the coverage report the run wrote
+	Coverage *Coverage `json:"coverage,omitempty"`
+	// TreeHash is the git tree of the working tree the tests ran on
+	TreeHash string `json:"tree_hash,omitempty"`
+	// UserCPU and SystemCPU are the CPU time the command used
+	UserCPU   time.Duration `json:"user_cpu,omitempty"`
+	SystemCPU time.Duration `json:"system_cpu,omitempty"`
+	// PeakRSS is the command's peak resident memory in bytes
+	PeakRSS int64 `json:"peak_rss,omitempty"`
+	// LimitExceeded names the resource limit that stopped the command
+	LimitExceeded string `json:"limit_exceeded,omitempty"`
+	// Attempts lists every run of a command that was retried, the last
+	// being the one this result describes
+	Attempts []Attempt `json:"attempts,omitempty"`
 	// GitHub Actions specific fields
 	WorkflowName    string              `json:"workflow_name,omitempty"`
 	RunID          int64               `json:"run_id,omitempty"`
@@ -27,11 +69,64 @@ type CommandResult struct {
 	JobResults     []GitHubActionJob   `json:"job_results,omitempty"`
 }
 
-// RunResult represents the result of running multiple commands
-type RunResult struct {
-	Timestamp time.Time                `json:"timestamp"`
-	Commands  map[string]CommandResult `json:"commands"`
-	Duration  time.Duration            `json:"duration"`
+// setDiagnostics records a tool's diagnostics and the counts derived from them
+func (r *CommandResult) setDiagnostics(diagnostics []Diagnostic) {
+	r.Diagnostics = diagnostics
+	r.FileCount = CountDiagnosticFiles(diagnostics)
+	r.ErrorCount, r.WarningCount = CountDiagnostics(diagnostics)
+	r.FixableCount = CountFixable(diagnostics)
+}
+
+// ResultStatus describes how a command execution ended
+type ResultStatus string
+
+const (
+	StatusPassed  ResultStatus = "passed"
+	StatusFailed  ResultStatus = "failed"
+	StatusSkipped ResultStatus = "skipped"
+	// StatusTimedOut and StatusCancelled mark commands that were stopped
+	// before they finished, as opposed to ones that ran and failed
+	StatusTimedOut  ResultStatus = "timed_out"
+	StatusCancelled ResultStatus = "cancelled"
+	// StatusUnstable marks test runs whose only failures are known-flaky tests
+	StatusUnstable ResultStatus = "unstable"
+	// StatusResourceExceeded marks commands stopped by a resource limit
+	StatusResourceExceeded ResultStatus = "resource_exceeded"
+)
+
+// Succeeded reports whether a command with the status did its job:
+// it passed, or only known-flaky tests failed
+func (s ResultStatus) Succeeded() bool {
+	return s == StatusPassed || s == StatusUnstable
+}
+
+// State returns the status of the result, deriving it from Passed for
+// results recorded before Status existed
+func (r CommandResult) State() ResultStatus {
+	if r.Status != "" {
+		return r.Status
+	}
+	if r.Passed {
+		return StatusPassed
+	}
+	return StatusFailed
+}
+
+// CommandType returns the command that produced the result, guessing it
+// from the command line for results recorded before Type existed
+func (r CommandResult) CommandType() CommandType {
+	if r.Type != "" {
+		return r.Type
+	}
+	return getCommandType(r.Command)
+}
+
+// statusFromPassed maps a pass/fail outcome to a result status
+func statusFromPassed(passed bool) ResultStatus {
+	if passed {
+		return StatusPassed
+	}
+	return StatusFailed
 }
 
 // CommandType represents the type of command being executed
@@ -42,6 +137,22 @@ const (
 	LintCheck       CommandType = "lint"
 	TestRunner      CommandType = "test"
 	GitHubActions   CommandType = "github_actions"
+	
+	// Go toolchain
+	GoBuild     CommandType = "go_build"
+	GoVet       CommandType = "go_vet"
+	GoTest      CommandType = "go_test"
+	Staticcheck CommandType = "staticcheck"
+	
+	// Python toolchain
+	Pytest CommandType = "pytest"
+	Mypy   CommandType = "mypy"
+	Ruff   CommandType = "ruff"
+	
+	// Rust toolchain
+	CargoCheck  CommandType = "cargo_check"
+	CargoClippy CommandType = "cargo_clippy"
+	CargoTest   CommandType = "cargo_test"
 )
 
 // Command represents a command to be executed
@@ -50,6 +161,33 @@ type Command struct {
 	Command string      `json:"command"`
 	Args    []string    `json:"args"`
 	Timeout time.Duration `json:"timeout"`
+	// Weight is the number of parallel slots the command occupies
+	Weight   int `json:"weight,omitempty"`
+	// Priority orders queued commands, higher runs first
+	Priority int `json:"priority,omitempty"`
+	// DependsOn lists commands that must pass before this one runs
+	DependsOn []CommandType `json:"depends_on,omitempty"`
+	// Inputs are globs of the files the command reads; commands with
+	// inputs reuse the result of an identical earlier passing run
+	Inputs []string `json:"inputs,omitempty"`
+	// IncrementalArgs replace Args when the command runs on changed files
+	IncrementalArgs []string `json:"incremental_args,omitempty"`
+	// IncrementalFiles are globs selecting the changed files passed to the command
+	IncrementalFiles []string `json:"incremental_files,omitempty"`
+	// ChangedFiles limits this run to the given files; see ForChangedFiles
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	// RunRef links the result to the run it is part of; see Runner.StartRun
+	RunRef string `json:"run_ref,omitempty"`
+	// Reports are globs of JUnit XML reports the command writes
+	Reports []string `json:"reports,omitempty"`
+	// Coverage locates the command's coverage report and its minimums
+	Coverage CoverageSettings `json:"coverage"`
+	// Success decides whether the command passed
+	Success SuccessCriteria `json:"success"`
+	// Retry decides whether a failed run is run again
+	Retry RetryPolicy `json:"retry"`
+	// Limits caps the resources the command may use
+	Limits ResourceLimits `json:"limits"`
 }
 
 // RunnerConfig holds configuration for the command runner
@@ -57,29 +195,62 @@ type RunnerConfig struct {
 	DefaultTimeout time.Duration `json:"default_timeout"`
 	MaxParallel    int           `json:"max_parallel"`
 	WorkingDir     string        `json:"working_dir"`
+	// KillGracePeriod is how long a timed out or cancelled command has to
+	// exit after SIGTERM before it is killed (default 5s)
+	KillGracePeriod time.Duration `json:"kill_grace_period,omitempty"`
 }
 
-// ResultHistory stores command execution history
+// ResultHistory stores command execution history. When backed by a
+// HistoryStore, results are persisted there and Results only holds the
+// ones that could not be written.
 type ResultHistory struct {
 	Results []CommandResult `json:"results"`
+	runs    []RunResult
+	store   *HistoryStore
 	mutex   sync.RWMutex
 }
 
+// NewResultHistory creates a history backed by a store. A nil store
+// keeps results in memory only.
+func NewResultHistory(store *HistoryStore) *ResultHistory {
+	return &ResultHistory{store: store}
+}
+
 // Add adds a result to the history
 func (h *ResultHistory) Add(result CommandResult) {
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
+	
+	if h.store != nil && h.store.Append(result) == nil {
+		return
+	}
 	h.Results = append(h.Results, result)
 }
 
+// all returns stored results followed by those kept in memory
+func (h *ResultHistory) all() []CommandResult {
+	var results []CommandResult
+	if h.store != nil {
+		if stored, err := h.store.Load(); err == nil {
+			results = stored
+		}
+	}
+	return append(results, h.Results...)
+}
+
 // GetLatest returns the latest results for each command type
 func (h *ResultHistory) GetLatest() map[CommandType]CommandResult {
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
 	
 	latest := make(map[CommandType]CommandResult)
+	if h.store != nil {
+		if stored, err := h.store.latestResults(); err == nil {
+			latest = stored
+		}
+	}
 	for _, result := range h.Results {
-		cmdType := getCommandType(result.Command)
+		cmdType := result.CommandType()
 		if existing, exists := latest[cmdType]; !exists || result.Timestamp.After(existing.Timestamp) {
 			latest[cmdType] = result
 		}
@@ -87,37 +258,102 @@ func (h *ResultHistory) GetLatest() map[CommandType]CommandResult {
 	return latest
 }
 
+// latestCoverage returns the coverage of the most recent result of a
+// command that reported coverage
+func (h *ResultHistory) latestCoverage(cmdType CommandType) *Coverage {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	
+	results := h.commandResults(cmdType, 1, func(result CommandResult) bool {
+		return result.Coverage != nil
+	})
+	if len(results) == 0 {
+		return nil
+	}
+	return results[0].Coverage
+}
+
+// commandResults returns up to limit of the newest results of one command
+// that keep accepts, oldest first. A limit of 0 returns all of them and a
+// nil keep accepts every result. The caller must hold the mutex.
+func (h *ResultHistory) commandResults(cmdType CommandType, limit int, keep func(CommandResult) bool) []CommandResult {
+	inMemory := newestResults(len(h.Results), func(i int) CommandResult {
+		return h.Results[i]
+	}, limit, func(result CommandResult) bool {
+		return result.CommandType() == cmdType && (keep == nil || keep(result))
+	})
+	if h.store == nil || (limit > 0 && len(inMemory) >= limit) {
+		return inMemory
+	}
+	
+	if limit > 0 {
+		limit -= len(inMemory)
+	}
+	stored, err := h.store.commandResults(cmdType, limit, keep)
+	if err != nil {
+		return inMemory
+	}
+	return append(stored, inMemory...)
+}
+
+// commandTypes returns the commands with results. The caller must hold
+// the mutex.
+func (h *ResultHistory) commandTypes() []CommandType {
+	seen := make(map[CommandType]bool)
+	var types []CommandType
+	if h.store != nil {
+		if stored, err := h.store.commandTypes(); err == nil {
+			for _, cmdType := range stored {
+				seen[cmdType] = true
+			}
+			types = stored
+		}
+	}
+	for _, result := range h.Results {
+		if cmdType := result.CommandType(); !seen[cmdType] {
+			seen[cmdType] = true
+			types = append(types, cmdType)
+		}
+	}
+	return types
+}
+
+// Recent returns up to limit of the newest results, oldest first
+func (h *ResultHistory) Recent(limit int) []CommandResult {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	
+	inMemory := newestResults(len(h.Results), func(i int) CommandResult {
+		return h.Results[i]
+	}, limit, nil)
+	if h.store == nil || len(inMemory) >= limit {
+		return inMemory
+	}
+	
+	stored, err := h.store.recentResults(limit-len(inMemory), nil)
+	if err != nil {
+		return inMemory
+	}
+	return append(stored, inMemory...)
+}
+
 // GetAll returns all results
 func (h *ResultHistory) GetAll() []CommandResult {
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
 	
-	results := make([]CommandResult, len(h.Results))
-	copy(results, h.Results)
-	return results
+	return h.all()
 }
 
 // Clear clears all results
 func (h *ResultHistory) Clear() {
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
-	h.Results = nil
-}
-
-// getCommandType determines command type from command string
-func getCommandType(command string) CommandType {
-	switch {
-	case strings.Contains(command, "tsc"):
-		return TypescriptCheck
-	case strings.Contains(command, "lint"):
-		return LintCheck
-	case strings.Contains(command, "test"):
-		return TestRunner
-	case strings.Contains(command, "github"):
-		return GitHubActions
-	default:
-		return CommandType(command)
+	if h.store != nil {
+		h.store.Clear()
 	}
+	h.Results = nil
+	h.runs = nil
 }
 
 // GitHubActionJob represents a single job in a GitHub Actions workflow
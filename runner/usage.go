@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// ResourceUsage is what a run cost beyond wall-clock time
+type ResourceUsage struct {
+	UserCPU   time.Duration
+	SystemCPU time.Duration
+	// PeakRSS is in bytes
+	PeakRSS int64
+}
+
+// processUsage reads the resource usage of an exited process. The kernel
+// includes the children the process waited for, which covers the tools
+// started through npm, npx or a shell, but not processes left running
+// after it exited.
+func processUsage(state *os.ProcessState) ResourceUsage {
+	if state == nil {
+		return ResourceUsage{}
+	}
+	return ResourceUsage{
+		UserCPU:   state.UserTime(),
+		SystemCPU: state.SystemTime(),
+		PeakRSS:   peakRSS(state),
+	}
+}
+
+// setUsage records the resource usage of a run
+func (r *CommandResult) setUsage(usage ResourceUsage) {
+	r.UserCPU = usage.UserCPU
+	r.SystemCPU = usage.SystemCPU
+	r.PeakRSS = usage.PeakRSS
+}
+
+// CPUTime returns the total CPU time of a run
+func (r CommandResult) CPUTime() time.Duration {
+	return r.UserCPU + r.SystemCPU
+}
+
+// CommandTotals add up the runs of a command recorded since the runner
+// started, leaving out cache hits. Unlike the history they are never
+// pruned, so they only grow.
+type CommandTotals struct {
+	Runs    int
+	CPUTime time.Duration
+}
+
+// usageTotals keeps the totals of each command
+type usageTotals struct {
+	totals map[CommandType]CommandTotals
+	mutex  sync.Mutex
+}
+
+// add counts a recorded result unless it was reused from the cache
+func (u *usageTotals) add(result CommandResult) {
+	if result.Cached {
+		return
+	}
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if u.totals == nil {
+		u.totals = make(map[CommandType]CommandTotals)
+	}
+	totals := u.totals[result.CommandType()]
+	totals.Runs++
+	totals.CPUTime += result.CPUTime()
+	u.totals[result.CommandType()] = totals
+}
+
+// Totals returns the totals of each command that recorded a result since
+// the runner started
+func (r *Runner) Totals() map[CommandType]CommandTotals {
+	r.totals.mutex.Lock()
+	defer r.totals.mutex.Unlock()
+
+	totals := make(map[CommandType]CommandTotals, len(r.totals.totals))
+	for cmdType, total := range r.totals.totals {
+		totals[cmdType] = total
+	}
+	return totals
+}
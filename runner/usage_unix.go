@@ -0,0 +1,23 @@
+//go:build !windows
+
+package runner
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// peakRSS returns the largest resident set size of an exited process and
+// the children it waited for, in bytes
+func peakRSS(state *os.ProcessState) int64 {
+	usage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+	// macOS reports bytes, other systems kilobytes
+	if runtime.GOOS == "darwin" || runtime.GOOS == "ios" {
+		return int64(usage.Maxrss)
+	}
+	return int64(usage.Maxrss) * 1024
+}
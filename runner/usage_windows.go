@@ -0,0 +1,10 @@
+//go:build windows
+
+package runner
+
+import "os"
+
+// peakRSS is not reported for Windows processes
+func peakRSS(state *os.ProcessState) int64 {
+	return 0
+}
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"sort"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,6 +18,7 @@ const (
 	ViewHistory
 	ViewLogs
 	ViewHelp
+	ViewOutput
 )
 
 // Model represents the application state
@@ -45,6 +48,15 @@ type Model struct {
 	logs       []LogEntry
 	maxLogs    int
 	
+	// Duration and issue trends, refreshed as results arrive
+	trends map[runner.CommandType]runner.Trend
+	
+	// Live command output
+	outputs           map[runner.CommandType][]string
+	outputGenerations map[runner.CommandType]uint64
+	outputCommand     runner.CommandType
+	maxOutputLines    int
+	
 	// Status
 	watcherActive bool
 	serverActive  bool
@@ -88,7 +100,7 @@ func NewModel(watchDir string) Model {
 	kwatchConfig, err := config.Load(watchDir)
 	if err != nil {
 		// Fall back to default config if loading fails
-		kwatchConfig = config.DefaultConfig()
+		kwatchConfig = config.DefaultConfigFor(watchDir)
 	}
 	
 	// Create runner configuration
@@ -101,23 +113,29 @@ func NewModel(watchDir string) Model {
 	// Create runner instance
 	r := runner.NewRunner(runnerConfig, kwatchConfig)
 	
-	return Model{
+	model := Model{
 		ready:        false,
 		width:        80,
 		height:       24,
 		viewMode:     ViewMain,
 		watchDir:     watchDir,
 		serverPort:   8080,
-		history:      &runner.ResultHistory{},
+		history:      r.History(),
 		running:      make(map[runner.CommandType]bool),
 		lastRun:      time.Now(),
 		runner:       r,
 		kwatchConfig: kwatchConfig,
 		logs:         make([]LogEntry, 0),
 		maxLogs:      100,
+		outputs:           make(map[runner.CommandType][]string),
+		outputGenerations: make(map[runner.CommandType]uint64),
+		maxOutputLines:    500,
 		watcherActive: false,
 		serverActive:  false,
 	}
+	model.refreshTrends()
+	
+	return model
 }
 
 // Init initializes the model
@@ -169,14 +187,8 @@ func (m *Model) AddLog(logType LogType, message, file, action string) {
 // GetCurrentCommandStatuses returns the current status of all commands
 func (m *Model) GetCurrentCommandStatuses() []CommandStatus {
 	latest := m.history.GetLatest()
-	statuses := make([]CommandStatus, 0, 4)
-	
-	commandTypes := []runner.CommandType{
-		runner.TypescriptCheck,
-		runner.LintCheck,
-		runner.TestRunner,
-		runner.GitHubActions,
-	}
+	commandTypes := m.commandTypes()
+	statuses := make([]CommandStatus, 0, len(commandTypes))
 	
 	for _, cmdType := range commandTypes {
 		status := CommandStatus{
@@ -195,6 +207,29 @@ func (m *Model) GetCurrentCommandStatuses() []CommandStatus {
 	return statuses
 }
 
+// commandTypes returns the commands shown in the status table: the enabled
+// commands of the configuration, plus the CI checkers
+func (m *Model) commandTypes() []runner.CommandType {
+	kwatchConfig := m.kwatchConfig
+	if kwatchConfig == nil {
+		kwatchConfig = config.DefaultConfig()
+	}
+	
+	var types []runner.CommandType
+	for _, checker := range runner.Checkers() {
+		if checker.Kind() == runner.KindCI {
+			types = append(types, checker.Type())
+		}
+	}
+	for name := range kwatchConfig.GetEnabledCommands() {
+		if runner.CommandType(name).Kind() != runner.KindCI {
+			types = append(types, runner.CommandType(name))
+		}
+	}
+	runner.SortCommandTypes(types)
+	return types
+}
+
 // GetRecentLogs returns the most recent log entries
 func (m *Model) GetRecentLogs(count int) []LogEntry {
 	if len(m.logs) == 0 {
@@ -209,18 +244,17 @@ func (m *Model) GetRecentLogs(count int) []LogEntry {
 	return m.logs[start:]
 }
 
+// historyViewRows is how many of the newest results the history view lists
+const historyViewRows = 200
+
 // GetHistoryForView returns command history formatted for display
 func (m *Model) GetHistoryForView() []runner.CommandResult {
-	results := m.history.GetAll()
+	results := m.history.Recent(historyViewRows)
 	
 	// Sort by timestamp (most recent first)
-	for i := 0; i < len(results); i++ {
-		for j := i + 1; j < len(results); j++ {
-			if results[i].Timestamp.Before(results[j].Timestamp) {
-				results[i], results[j] = results[j], results[i]
-			}
-		}
-	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Timestamp.After(results[j].Timestamp)
+	})
 	
 	return results
 }
@@ -246,33 +280,69 @@ func (m *Model) IsAnyCommandRunning() bool {
 	return false
 }
 
-// AddCommandResult adds a command result to the history
+// AddCommandResult records that a command finished. The runner has
+// already added the result to the shared history.
 func (m *Model) AddCommandResult(result runner.CommandResult) {
-	m.history.Add(result)
-	m.SetCommandRunning(getCommandType(result.Command), false)
+	m.SetCommandRunning(result.CommandType(), false)
 	
-	status := "PASSED"
-	if !result.Passed {
-		status = "FAILED"
+	status := strings.ToUpper(string(result.State()))
+	if attempts := result.AttemptSummary(); attempts != "" {
+		status += " " + attempts
 	}
 	
 	m.AddLog(LogCommandEnd, "Command "+status, "", result.Command)
+	m.refreshTrends()
 }
 
-// getCommandType determines command type from command string
-func getCommandType(command string) runner.CommandType {
+// AddEvent logs a state change or regression reported by the runner
+func (m *Model) AddEvent(event runner.Event) {
 	switch {
-	case command == "tsc" || command == "typescript":
-		return runner.TypescriptCheck
-	case command == "lint":
-		return runner.LintCheck
-	case command == "test":
-		return runner.TestRunner
-	case command == "github_actions":
-		return runner.GitHubActions
+	case event.Kind == runner.EventRegression:
+		m.AddLog(LogWarning, "Regression: "+event.Message, "", string(event.Command))
+	case event.Status.Succeeded():
+		m.AddLog(LogInfo, event.Message, "", string(event.Command))
 	default:
-		return runner.CommandType(command)
+		m.AddLog(LogError, event.Message, "", string(event.Command))
+	}
+}
+
+// refreshTrends recomputes the trends shown in the status table
+func (m *Model) refreshTrends() {
+	m.trends = make(map[runner.CommandType]runner.Trend)
+	for _, trend := range m.runner.Trends() {
+		m.trends[trend.Command] = trend
+	}
+}
+
+// GetTrend returns the trend of a command
+func (m *Model) GetTrend(cmdType runner.CommandType) (runner.Trend, bool) {
+	trend, exists := m.trends[cmdType]
+	return trend, exists
+}
+
+// AddOutputLine appends a line of live output for a command. Output from
+// a newer run replaces the previous run's output; lines from older runs
+// are ignored.
+func (m *Model) AddOutputLine(line runner.OutputLine) {
+	current := m.outputGenerations[line.Command]
+	if line.Generation < current {
+		return
+	}
+	if line.Generation > current {
+		m.outputGenerations[line.Command] = line.Generation
+		m.outputs[line.Command] = nil
+	}
+	
+	lines := append(m.outputs[line.Command], line.Text)
+	if len(lines) > m.maxOutputLines {
+		lines = lines[len(lines)-m.maxOutputLines:]
 	}
+	m.outputs[line.Command] = lines
+}
+
+// GetOutputLines returns the latest output lines for a command
+func (m *Model) GetOutputLines(cmdType runner.CommandType) []string {
+	return m.outputs[cmdType]
 }
 
 // NavigateUp moves selection up
@@ -294,7 +364,7 @@ func (m *Model) NavigateDown() {
 func (m *Model) getMaxRows() int {
 	switch m.viewMode {
 	case ViewMain:
-		return 4 // Four command types (including GitHub Actions)
+		return len(m.commandTypes())
 	case ViewHistory:
 		return len(m.GetHistoryForView())
 	case ViewLogs:
@@ -309,6 +379,7 @@ func (m *Model) GetStatusSummary() string {
 	statuses := m.GetCurrentCommandStatuses()
 	passed := 0
 	failed := 0
+	unstable := 0
 	running := 0
 	
 	for _, status := range statuses {
@@ -317,6 +388,8 @@ func (m *Model) GetStatusSummary() string {
 		} else if status.Result != nil {
 			if status.Result.Passed {
 				passed++
+			} else if status.Result.State() == runner.StatusUnstable {
+				unstable++
 			} else {
 				failed++
 			}
@@ -329,6 +402,9 @@ func (m *Model) GetStatusSummary() string {
 	if failed > 0 {
 		return "Failed"
 	}
+	if unstable > 0 {
+		return "Unstable"
+	}
 	if passed == len(statuses) {
 		return "All Passed"
 	}
@@ -344,7 +420,7 @@ func (m *Model) GetErrorMetrics() (int, int) {
 	
 	for _, status := range statuses {
 		if status.Result != nil && !status.Result.Passed {
-			if status.Type == runner.TestRunner {
+			if runner.IsTestCommand(status.Type) {
 				// For tests, count failed tests as errors
 				totalErrors += status.Result.FailedTests
 			} else {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	
 	"github.com/charmbracelet/lipgloss"
+	"kwatch/runner"
 )
 
 // Color palette
@@ -57,6 +58,10 @@ var (
 		Foreground(runningColor).
 		Bold(true)
 
+	statusUnstableStyle = lipgloss.NewStyle().
+		Foreground(warningColor).
+		Bold(true)
+
 	// Text styles
 	normalTextStyle = lipgloss.NewStyle().
 		Foreground(textColor)
@@ -179,14 +184,120 @@ func GetStatusIcon(passed bool, running bool) string {
 	return "✗"
 }
 
-// GetCommandStyle returns appropriate style for command type
+// GetResultStatus returns the status text and style for a finished command
+func GetResultStatus(result runner.CommandResult) (string, lipgloss.Style) {
+	text, style := resultStatus(result)
+	if attempts := result.AttemptSummary(); attempts != "" {
+		text += " " + attempts
+	}
+	return text, style
+}
+
+// resultStatus returns the status text and style for a result's state
+func resultStatus(result runner.CommandResult) (string, lipgloss.Style) {
+	switch result.State() {
+	case runner.StatusPassed:
+		return GetStatusIcon(true, false) + " Passed", statusPassStyle
+	case runner.StatusSkipped:
+		return runner.StatusSymbol(runner.StatusSkipped) + " Skipped", dimTextStyle
+	case runner.StatusTimedOut:
+		return runner.StatusSymbol(runner.StatusTimedOut) + " Timed out", statusFailStyle
+	case runner.StatusCancelled:
+		return runner.StatusSymbol(runner.StatusCancelled) + " Cancelled", dimTextStyle
+	case runner.StatusUnstable:
+		return runner.StatusSymbol(runner.StatusUnstable) + " Unstable", statusUnstableStyle
+	case runner.StatusResourceExceeded:
+		return runner.StatusSymbol(runner.StatusResourceExceeded) + " Over " + limitLabel(result.LimitExceeded), statusFailStyle
+	default:
+		return GetStatusIcon(false, false) + " Failed", statusFailStyle
+	}
+}
+
+// limitLabel names an exceeded resource limit
+func limitLabel(limit string) string {
+	switch limit {
+	case runner.LimitMemory:
+		return "memory"
+	case runner.LimitCPUTime:
+		return "CPU time"
+	case runner.LimitOpenFiles:
+		return "open files"
+	case runner.LimitProcesses:
+		return "processes"
+	default:
+		return "limit"
+	}
+}
+
+// resultDuration formats how long a result took, or notes it was cached
+func resultDuration(result runner.CommandResult) string {
+	if result.Cached {
+		return "cached"
+	}
+	return FormatDuration(result.Duration.Milliseconds())
+}
+
+// FormatCoverage formats the line coverage of a result with its change
+// since the previous run, highlighting drops
+func FormatCoverage(coverage *runner.Coverage) string {
+	if coverage == nil || !coverage.Lines.Reported() {
+		return "-"
+	}
+	
+	text := fmt.Sprintf("%.1f%%", coverage.Lines.Percent)
+	if coverage.Delta == nil {
+		return text
+	}
+	
+	delta := coverage.Delta.Lines
+	switch {
+	case delta <= -0.05:
+		return text + " " + statusFailStyle.Render(fmt.Sprintf("↓%.1f", -delta))
+	case delta >= 0.05:
+		return text + " " + statusPassStyle.Render(fmt.Sprintf("↑%.1f", delta))
+	default:
+		return text
+	}
+}
+
+// FormatTrend draws a sparkline of a command's recent durations,
+// highlighting it when the duration or issue count regressed
+func FormatTrend(trend runner.Trend) string {
+	sparkline := trend.DurationSparkline(12)
+	if sparkline == "" {
+		return "-"
+	}
+	if len(trend.Regressions) > 0 {
+		return statusFailStyle.Render(sparkline + " ↑")
+	}
+	return sparkline
+}
+
+// FormatUsage formats the CPU time and peak RSS of a result, or dashes for
+// results that did not run a process
+func FormatUsage(result runner.CommandResult) (cpu, rss string) {
+	cpu, rss = "-", "-"
+	if cpuTime := result.CPUTime(); cpuTime > 0 {
+		cpu = fmt.Sprintf("%.2fs", cpuTime.Seconds())
+	}
+	if result.PeakRSS > 0 {
+		rss = runner.FormatBytes(result.PeakRSS)
+	}
+	return cpu, rss
+}
+
+// GetCommandStyle returns appropriate style for the kind of a command type
 func GetCommandStyle(commandType string) lipgloss.Style {
-	switch commandType {
-	case "typescript":
+	checker, exists := runner.LookupChecker(runner.CommandType(commandType))
+	if !exists {
+		return normalTextStyle
+	}
+	switch checker.Kind() {
+	case runner.KindCheck:
 		return commandTSCStyle
-	case "lint":
+	case runner.KindLint:
 		return commandLintStyle
-	case "test":
+	case runner.KindTest:
 		return commandTestStyle
 	default:
 		return normalTextStyle
@@ -5,12 +5,14 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fsnotify/fsnotify"
 	"golang.org/x/term"
+	"kwatch/runner"
 )
 
 // TUI represents the main TUI application
@@ -73,6 +75,27 @@ func (t *TUI) Start() error {
 		tea.WithOutput(os.Stderr),
 	)
 	
+	// Forward live command output to the program
+	if t.model.runner != nil {
+		unsubscribe := t.model.runner.Subscribe(func(line runner.OutputLine) {
+			t.program.Send(outputLineMsg{line: line})
+		})
+		defer unsubscribe()
+		
+		// Forward state changes and regressions to the activity log
+		unsubscribeEvents := t.model.runner.SubscribeEvents(func(event runner.Event) {
+			t.program.Send(eventMsg{event: event})
+		})
+		defer unsubscribeEvents()
+		
+		// Results arrive here however their run was started, so commands
+		// run together by the runner show up as each one finishes
+		unsubscribeResults := t.model.runner.SubscribeResults(func(result runner.CommandResult) {
+			t.program.Send(commandResultMsg{result: result})
+		})
+		defer unsubscribeResults()
+	}
+	
 	// Start file watcher
 	if err := t.startFileWatcher(); err != nil {
 		return fmt.Errorf("failed to start file watcher: %w", err)
@@ -128,38 +151,13 @@ func (t *TUI) setupLogging() error {
 
 // startFileWatcher starts the file system watcher
 func (t *TUI) startFileWatcher() error {
-	// Watch the main directory
+	// Watch the main directory and every source directory below it
 	if err := t.watcher.Add(t.watchDir); err != nil {
 		return fmt.Errorf("failed to watch directory %s: %w", t.watchDir, err)
 	}
-	
-	// Watch common source directories
-	watchDirs := []string{
-		"src",
-		"lib",
-		"components",
-		"pages",
-		"utils",
-		"types",
-		"hooks",
-		"services",
-		"api",
-		"styles",
-		"public",
-		"tests",
-		"__tests__",
-		"test",
-		"spec",
-	}
-	
-	for _, dir := range watchDirs {
-		dirPath := filepath.Join(t.watchDir, dir)
-		if _, err := os.Stat(dirPath); err == nil {
-			if err := t.addWatchRecursive(dirPath); err != nil {
-				// Log error but continue
-				t.logError(fmt.Sprintf("Failed to watch directory %s: %v", dirPath, err))
-			}
-		}
+	if err := t.addWatchRecursive(t.watchDir); err != nil {
+		// Log error but continue
+		t.logError(fmt.Sprintf("Failed to watch directory %s: %v", t.watchDir, err))
 	}
 	
 	// Start watching in a goroutine
@@ -172,30 +170,57 @@ func (t *TUI) startFileWatcher() error {
 func (t *TUI) addWatchRecursive(root string) error {
 	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
+			// Directories removed while walking are not watched
+			if path != root && os.IsNotExist(err) {
+				return nil
+			}
 			return err
 		}
 		
-		// Skip hidden directories and files
-		if info.IsDir() && (info.Name()[0] == '.' || info.Name() == "node_modules") {
-			return filepath.SkipDir
+		// Only watch directories
+		if !info.IsDir() {
+			return nil
 		}
 		
-		// Only watch directories
-		if info.IsDir() {
-			return t.watcher.Add(path)
+		// Skip hidden and build/temp directories
+		if path != root && isIgnoredDir(info.Name()) {
+			return filepath.SkipDir
 		}
 		
-		return nil
+		return t.watcher.Add(path)
 	})
 }
 
-// watchFiles processes file system events with debouncing
+// watchFiles processes file system events with debouncing. Changes are
+// collected until no event has arrived for the debounce delay, then sent
+// to the program as one batch.
 func (t *TUI) watchFiles() {
-	var lastEventTime time.Time
 	debounceDelay := 2 * time.Second
+	pending := make(map[string]bool)
+	var lastFile, lastAction string
+	var flush <-chan time.Time
 	
 	for {
 		select {
+		case <-flush:
+			flush = nil
+			
+			files := make([]string, 0, len(pending))
+			for file := range pending {
+				files = append(files, file)
+			}
+			sort.Strings(files)
+			pending = make(map[string]bool)
+			
+			// Send file change message to the program
+			if t.program != nil {
+				t.program.Send(fileChangeMsg{
+					file:   lastFile,
+					action: lastAction,
+					files:  files,
+				})
+			}
+		
 		case event, ok := <-t.watcher.Events:
 			if !ok {
 				// Watcher channel closed, notify that watcher stopped
@@ -213,27 +238,28 @@ func (t *TUI) watchFiles() {
 				continue
 			}
 			
-			// Filter relevant file types
-			if !t.isRelevantFile(event.Name) {
-				continue
+			// Watch directories created after the watcher started
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if !isIgnoredDir(info.Name()) {
+						if err := t.addWatchRecursive(event.Name); err != nil {
+							t.logError(fmt.Sprintf("Failed to watch directory %s: %v", event.Name, err))
+						}
+					}
+					continue
+				}
 			}
 			
-			// Debounce events - ignore if too soon after last event
-			now := time.Now()
-			if now.Sub(lastEventTime) < debounceDelay {
+			// Filter relevant file types
+			if !t.isRelevantFile(event.Name) {
 				continue
 			}
-			lastEventTime = now
 			
+			// Debounce events - restart the quiet period on every change
 			action := t.getFileAction(event.Op)
-			
-			// Send file change message to the program
-			if t.program != nil {
-				t.program.Send(fileChangeMsg{
-					file:   event.Name,
-					action: action,
-				})
-			}
+			pending[event.Name] = true
+			lastFile, lastAction = event.Name, action
+			flush = time.After(debounceDelay)
 			
 			// Log the file change
 			t.logFileChange(event.Name, action)
@@ -262,6 +288,27 @@ func (t *TUI) watchFiles() {
 	}
 }
 
+// ignoreDirs are build/temp directories whose changes are never relevant
+var ignoreDirs = []string{
+	"node_modules", "dist", "build", ".next", ".nuxt",
+	"coverage", ".nyc_output", ".cache", ".tmp", "tmp",
+	".kwatch", ".git", ".vscode", ".idea",
+	"__pycache__", ".pytest_cache", "target",
+}
+
+// isIgnoredDir reports whether a directory is hidden or one of ignoreDirs
+func isIgnoredDir(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return true
+	}
+	for _, ignoreDir := range ignoreDirs {
+		if name == ignoreDir {
+			return true
+		}
+	}
+	return false
+}
+
 // isRelevantFile checks if a file change is relevant for monitoring
 func (t *TUI) isRelevantFile(filename string) bool {
 	// Ignore hidden files and directories
@@ -270,15 +317,8 @@ func (t *TUI) isRelevantFile(filename string) bool {
 	}
 	
 	// Ignore common build/temp directories
-	ignoreDirs := []string{
-		"node_modules/", "dist/", "build/", ".next/", ".nuxt/",
-		"coverage/", ".nyc_output/", ".cache/", ".tmp/", "tmp/",
-		".kwatch/", ".git/", ".vscode/", ".idea/",
-		"__pycache__/", ".pytest_cache/",
-	}
-	
 	for _, ignoreDir := range ignoreDirs {
-		if strings.Contains(filename, ignoreDir) {
+		if strings.Contains(filename, ignoreDir+"/") {
 			return false
 		}
 	}
@@ -296,39 +336,11 @@ func (t *TUI) isRelevantFile(filename string) bool {
 		}
 	}
 	
-	// Only watch source files
-	relevantExts := []string{
-		".ts", ".tsx", ".js", ".jsx",
-		".json", ".yaml", ".yml",
-		".css", ".scss", ".sass", ".less",
-		".html", ".htm", ".vue",
-		".md", ".mdx",
-		".graphql", ".gql",
-		".prisma", ".proto",
-	}
-	
-	for _, relevantExt := range relevantExts {
-		if ext == relevantExt {
-			return true
-		}
+	// Only watch the files a registered checker watches
+	if rel, err := filepath.Rel(t.watchDir, filename); err == nil {
+		filename = rel
 	}
-	
-	// Check for specific config filenames
-	base := filepath.Base(filename)
-	relevantFiles := []string{
-		"package.json", "tsconfig.json", "jsconfig.json",
-		".eslintrc.js", ".eslintrc.json", ".prettierrc",
-		"jest.config.js", "vite.config.js", "webpack.config.js",
-		"next.config.js", "tailwind.config.js",
-	}
-	
-	for _, relevantFile := range relevantFiles {
-		if base == relevantFile {
-			return true
-		}
-	}
-	
-	return false
+	return runner.IsWatchedFile(filename)
 }
 
 // getFileAction converts fsnotify operation to readable action
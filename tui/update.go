@@ -3,8 +3,7 @@ package tui
 import (
 	"context"
 	"fmt"
-	"os/exec"
-	"strings"
+	"path/filepath"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -32,10 +31,22 @@ type (
 		cmdType runner.CommandType
 	}
 	
+	// Command output line message
+	outputLineMsg struct {
+		line runner.OutputLine
+	}
+	
+	// State change or regression message
+	eventMsg struct {
+		event runner.Event
+	}
+	
 	// File change message
 	fileChangeMsg struct {
 		file   string
 		action string
+		// files holds every file changed since the last message
+		files []string
 	}
 	
 	// Status update message
@@ -72,6 +83,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	
 	// Handle command results
 	case commandResultMsg:
+		// A newer run of this command is already under way
+		if msg.result.Superseded {
+			m.AddLog(LogInfo, "Discarded superseded result", "", msg.result.Command)
+			return m, nil
+		}
 		m.AddCommandResult(msg.result)
 		return m, nil
 	
@@ -80,14 +96,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.SetCommandRunning(msg.cmdType, true)
 		return m, nil
 	
+	// Handle live command output
+	case outputLineMsg:
+		m.AddOutputLine(msg.line)
+		return m, nil
+	
+	// Handle state changes and regressions
+	case eventMsg:
+		m.AddEvent(msg.event)
+		return m, nil
+	
 	// Handle file changes
 	case fileChangeMsg:
-		m.AddLog(LogFileChange, "File changed", msg.file, msg.action)
-		// Only run commands if not already running
-		if !m.IsAnyCommandRunning() {
-			return m, m.runCommandsOnChange()
+		if len(msg.files) > 1 {
+			m.AddLog(LogFileChange, fmt.Sprintf("%d files changed", len(msg.files)), msg.file, msg.action)
+		} else {
+			m.AddLog(LogFileChange, "File changed", msg.file, msg.action)
 		}
-		return m, nil
+		changed := msg.files
+		if len(changed) == 0 {
+			changed = []string{msg.file}
+		}
+		// Restart commands so results always describe the latest code
+		return m, m.runCommandsOnChange(changed)
 	
 	// Handle status updates
 	case statusUpdateMsg:
@@ -150,6 +181,17 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.selectedRow = 0
 		return m, nil
 	
+	// Live output of the selected command
+	case "o":
+		if m.viewMode == ViewMain {
+			statuses := m.GetCurrentCommandStatuses()
+			if m.selectedRow >= 0 && m.selectedRow < len(statuses) {
+				m.outputCommand = statuses[m.selectedRow].Type
+				m.viewMode = ViewOutput
+			}
+		}
+		return m, nil
+	
 	// Navigation
 	case "up", "k":
 		m.NavigateUp()
@@ -213,53 +255,95 @@ func (m Model) handleEnterKey() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// runAllCommands runs all configured commands
+// runAllCommands runs all configured commands as one run through the
+// runner, so commands whose dependencies fail are skipped. Results arrive
+// through the runner's result subscription as each command finishes.
 func (m Model) runAllCommands() tea.Cmd {
 	if m.runner == nil {
 		return nil
 	}
 	
-	// Create individual commands for each enabled command type
-	var cmds []tea.Cmd
-	enabledCommands := m.kwatchConfig.GetEnabledCommands()
-	
-	for name := range enabledCommands {
-		var cmdType runner.CommandType
-		switch name {
-		case "typescript":
-			cmdType = runner.TypescriptCheck
-		case "lint":
-			cmdType = runner.LintCheck
-		case "test":
-			cmdType = runner.TestRunner
-		default:
-			cmdType = runner.CommandType(name)
+	// Mark every enabled command as running before any result can arrive
+	var starts []tea.Cmd
+	for name := range m.kwatchConfig.GetEnabledCommands() {
+		ct := runner.CommandType(name)
+		starts = append(starts, tea.Cmd(func() tea.Msg {
+			return commandStartMsg{cmdType: ct}
+		}))
+	}
+	
+	return tea.Sequence(
+		tea.Batch(starts...),
+		tea.Cmd(func() tea.Msg {
+			m.runner.RunAll(context.Background(), runner.TriggerManual)
+			return nil
+		}),
+	)
+}
+
+// runCommandsOnChange runs commands when files change, superseding any
+// runs of them that are still in flight. Commands configured for
+// incremental runs only check the changed files.
+func (m Model) runCommandsOnChange(changedFiles []string) tea.Cmd {
+	if m.runner == nil {
+		return nil
+	}
+	
+	// Run the configured checkers that run on file changes and watch one
+	// of the changed files; tests only run on demand
+	var starts []tea.Cmd
+	var commands []runner.Command
+	for _, checker := range runner.Checkers() {
+		if !checker.Kind().RunsOnChange() || !m.watchesAny(checker, changedFiles) {
+			continue
+		}
+		
+		// Skip commands that are not configured, and incremental commands
+		// none of the changed files are relevant to
+		configCmd, exists := m.runner.ConfiguredCommand(checker.Type())
+		if !exists {
+			continue
+		}
+		configCmd, relevant := configCmd.ForChangedFiles(m.watchDir, changedFiles)
+		if !relevant {
+			continue
 		}
 		
-		// Create a command to run this specific command type
-		ct := cmdType
-		cmds = append(cmds, 
-			tea.Cmd(func() tea.Msg {
-				return commandStartMsg{cmdType: ct}
-			}),
-			m.runSpecificCommand(ct),
-		)
+		ct := configCmd.Type
+		commands = append(commands, configCmd)
+		starts = append(starts, tea.Cmd(func() tea.Msg {
+			return commandStartMsg{cmdType: ct}
+		}))
+	}
+	if len(commands) == 0 {
+		return nil
 	}
 	
-	return tea.Batch(cmds...)
+	return tea.Sequence(
+		tea.Batch(starts...),
+		tea.Cmd(func() tea.Msg {
+			run := m.runner.RunChanged(context.Background(), changedFiles, commands)
+			return supersededResults(run)
+		}),
+	)
 }
 
-// runCommandsOnChange runs commands when files change
-func (m Model) runCommandsOnChange() tea.Cmd {
-	// Run TypeScript check and lint on most file changes
-	// Only run tests if test files changed
-	return tea.Batch(
-		m.runSpecificCommand(runner.TypescriptCheck),
-		m.runSpecificCommand(runner.LintCheck),
-	)
+// watchesAny reports whether the checker watches any of the changed files
+func (m Model) watchesAny(checker runner.Checker, changedFiles []string) bool {
+	for _, file := range changedFiles {
+		if filepath.IsAbs(file) {
+			if rel, err := filepath.Rel(m.watchDir, file); err == nil {
+				file = rel
+			}
+		}
+		if runner.Watches(checker, file) {
+			return true
+		}
+	}
+	return false
 }
 
-// runSpecificCommand runs a specific command type
+// runSpecificCommand runs a specific command type as a run of its own
 func (m Model) runSpecificCommand(cmdType runner.CommandType) tea.Cmd {
 	if m.runner == nil {
 		return nil
@@ -267,35 +351,9 @@ func (m Model) runSpecificCommand(cmdType runner.CommandType) tea.Cmd {
 	
 	return tea.Cmd(func() tea.Msg {
 		// Find the command configuration for this type
-		enabledCommands := m.kwatchConfig.GetEnabledCommands()
-		
-		var configCmd *runner.Command
-		for name, cmd := range enabledCommands {
-			var mappedType runner.CommandType
-			switch name {
-			case "typescript":
-				mappedType = runner.TypescriptCheck
-			case "lint":
-				mappedType = runner.LintCheck
-			case "test":
-				mappedType = runner.TestRunner
-			default:
-				mappedType = runner.CommandType(name)
-			}
-			
-			if mappedType == cmdType {
-				timeout := m.kwatchConfig.GetTimeout(name)
-				configCmd = &runner.Command{
-					Type:    cmdType,
-					Command: cmd.Command,
-					Args:    cmd.Args,
-					Timeout: timeout,
-				}
-				break
-			}
-		}
+		configCmd, exists := m.runner.ConfiguredCommand(cmdType)
 		
-		if configCmd == nil {
+		if !exists {
 			// Fallback for unknown command types
 			return commandResultMsg{
 				result: runner.CommandResult{
@@ -308,15 +366,28 @@ func (m Model) runSpecificCommand(cmdType runner.CommandType) tea.Cmd {
 			}
 		}
 		
-		// Execute the command using the runner
-		ctx := context.Background()
-		result := m.runner.RunCommand(ctx, *configCmd)
-		
-		// Send the result
-		return commandResultMsg{result: result}
+		run := m.runner.RunOne(context.Background(), runner.TriggerManual, configCmd)
+		return supersededResults(run)
 	})
 }
 
+// supersededResults reports the superseded results of a run. Recorded
+// results arrive through the runner's result subscription.
+func supersededResults(run runner.RunResult) tea.Msg {
+	var reports []tea.Cmd
+	for _, result := range run.Commands {
+		if !result.Superseded {
+			continue
+		}
+		msg := commandResultMsg{result: result}
+		reports = append(reports, func() tea.Msg { return msg })
+	}
+	if len(reports) == 0 {
+		return nil
+	}
+	return tea.Batch(reports...)()
+}
+
 
 // checkStatus checks the current status of watcher and server
 func (m Model) checkStatus() tea.Cmd {
@@ -386,67 +457,6 @@ func (m Model) startFileWatcher() tea.Cmd {
 
 // Utility functions
 
-// isValidCommand checks if a command is valid/available
-func isValidCommand(cmdType runner.CommandType) bool {
-	switch cmdType {
-	case runner.TypescriptCheck:
-		return commandExists("npx")
-	case runner.LintCheck:
-		return commandExists("npm")
-	case runner.TestRunner:
-		return commandExists("npm")
-	default:
-		return false
-	}
-}
-
-// commandExists checks if a command exists in PATH
-func commandExists(command string) bool {
-	_, err := exec.LookPath(command)
-	return err == nil
-}
-
-// parseCommandOutput parses command output for additional information
-func parseCommandOutput(cmdType runner.CommandType, output string) (count int, summary string) {
-	switch cmdType {
-	case runner.TypescriptCheck:
-		// Parse TypeScript output
-		lines := strings.Split(output, "\n")
-		errorCount := 0
-		for _, line := range lines {
-			if strings.Contains(line, "error TS") {
-				errorCount++
-			}
-		}
-		return errorCount, fmt.Sprintf("%d errors", errorCount)
-	
-	case runner.LintCheck:
-		// Parse ESLint output
-		lines := strings.Split(output, "\n")
-		problemCount := 0
-		for _, line := range lines {
-			if strings.Contains(line, "problem") {
-				problemCount++
-			}
-		}
-		return problemCount, fmt.Sprintf("%d problems", problemCount)
-	
-	case runner.TestRunner:
-		// Parse test output
-		lines := strings.Split(output, "\n")
-		testCount := 0
-		for _, line := range lines {
-			if strings.Contains(line, "test") {
-				testCount++
-			}
-		}
-		return testCount, fmt.Sprintf("%d tests", testCount)
-	
-	default:
-		return 0, "Unknown"
-	}
-}
-
 // formatError formats error messages for display
 func formatError(err error) string {
 	if err == nil {
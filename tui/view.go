@@ -24,6 +24,8 @@ func (m Model) View() string {
 		return m.renderLogsView()
 	case ViewHelp:
 		return m.renderHelpView()
+	case ViewOutput:
+		return m.renderOutputView()
 	default:
 		return m.renderMainView()
 	}
@@ -91,6 +93,22 @@ func (m Model) renderLogsView() string {
 	)
 }
 
+// renderOutputView renders the live output of the selected command
+func (m Model) renderOutputView() string {
+	header := m.renderHeader()
+	output := m.renderCommandOutput()
+	statusBar := m.renderStatusBar()
+	
+	availableHeight := m.height - headerHeight - statusBarHeight - 2
+	outputStyled := panelStyle.Width(m.width - 4).Height(availableHeight).Render(output)
+	
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		outputStyled,
+		statusBar,
+	)
+}
+
 // renderHelpView renders the help view
 func (m Model) renderHelpView() string {
 	header := m.renderHeader()
@@ -122,6 +140,8 @@ func (m Model) renderHeader() string {
 		viewIndicator = "Logs"
 	case ViewHelp:
 		viewIndicator = "Help"
+	case ViewOutput:
+		viewIndicator = "Output"
 	}
 	
 	// Directory info
@@ -173,18 +193,20 @@ func (m Model) renderCommandTable() string {
 	// Table header
 	header := lipgloss.JoinHorizontal(lipgloss.Left,
 		tableHeaderStyle.Width(20).Render("Command"),
-		tableHeaderStyle.Width(12).Render("Status"),
+		tableHeaderStyle.Width(24).Render("Status"),
 		tableHeaderStyle.Width(12).Render("Duration"),
-		tableHeaderStyle.Width(20).Render("Last Run"),
+		tableHeaderStyle.Width(12).Render("Last Run"),
 		tableHeaderStyle.Width(12).Render("Count"),
+		tableHeaderStyle.Width(14).Render("Coverage"),
+		tableHeaderStyle.Width(16).Render("Trend"),
 	)
 	
 	// Table rows
 	rows := make([]string, len(statuses))
 	for i, status := range statuses {
 		// Command name
-		cmdName := string(status.Type)
-		cmdStyle := GetCommandStyle(cmdName)
+		cmdName := status.Type.Name()
+		cmdStyle := GetCommandStyle(string(status.Type))
 		
 		// Status
 		statusText := "Not Run"
@@ -193,19 +215,13 @@ func (m Model) renderCommandTable() string {
 			statusText = "Running " + GetStatusIcon(false, true)
 			statusStyle = GetStatusStyle(false, true)
 		} else if status.Result != nil {
-			statusText = GetStatusIcon(status.Result.Passed, false)
-			if status.Result.Passed {
-				statusText += " Passed"
-			} else {
-				statusText += " Failed"
-			}
-			statusStyle = GetStatusStyle(status.Result.Passed, false)
+			statusText, statusStyle = GetResultStatus(*status.Result)
 		}
 		
 		// Duration
 		duration := "-"
 		if status.Result != nil {
-			duration = FormatDuration(status.Result.Duration.Milliseconds())
+			duration = resultDuration(*status.Result)
 		}
 		
 		// Last run
@@ -217,7 +233,7 @@ func (m Model) renderCommandTable() string {
 		// Count - show test results as PASS/TOTAL or errors/files
 		count := "-"
 		if status.Result != nil {
-			if status.Type == runner.TestRunner {
+			if runner.IsTestCommand(status.Type) {
 				// For tests, show PASS/TOTAL format
 				if status.Result.TotalTests > 0 {
 					count = fmt.Sprintf("%d/%d", status.Result.PassedTests, status.Result.TotalTests)
@@ -227,8 +243,8 @@ func (m Model) renderCommandTable() string {
 				} else {
 					count = "0"
 				}
-			} else if status.Type == runner.GitHubActions {
-				// For GitHub Actions, show failed jobs / total jobs
+			} else if status.Type.Kind() == runner.KindCI {
+				// For CI, show failed jobs / total jobs
 				if len(status.Result.JobResults) > 0 {
 					failedJobs := 0
 					for _, job := range status.Result.JobResults {
@@ -257,6 +273,18 @@ func (m Model) renderCommandTable() string {
 			}
 		}
 		
+		// Coverage with its change since the previous run
+		coverage := "-"
+		if status.Result != nil {
+			coverage = FormatCoverage(status.Result.Coverage)
+		}
+		
+		// Sparkline of recent durations
+		trend := "-"
+		if t, exists := m.GetTrend(status.Type); exists {
+			trend = FormatTrend(t)
+		}
+		
 		// Row style
 		rowStyle := tableCellStyle
 		if m.viewMode == ViewMain && i == m.selectedRow {
@@ -265,10 +293,12 @@ func (m Model) renderCommandTable() string {
 		
 		row := lipgloss.JoinHorizontal(lipgloss.Left,
 			rowStyle.Width(20).Render(cmdStyle.Render(cmdName)),
-			rowStyle.Width(12).Render(statusStyle.Render(statusText)),
+			rowStyle.Width(24).Render(statusStyle.Render(statusText)),
 			rowStyle.Width(12).Render(duration),
-			rowStyle.Width(20).Render(lastRun),
+			rowStyle.Width(12).Render(lastRun),
 			rowStyle.Width(12).Render(count),
+			rowStyle.Width(14).Render(coverage),
+			rowStyle.Width(16).Render(trend),
 		)
 		
 		rows[i] = row
@@ -345,9 +375,11 @@ func (m Model) renderHistoryTable() string {
 	// Table header
 	header := lipgloss.JoinHorizontal(lipgloss.Left,
 		tableHeaderStyle.Width(16).Render("Command"),
-		tableHeaderStyle.Width(12).Render("Status"),
+		tableHeaderStyle.Width(24).Render("Status"),
 		tableHeaderStyle.Width(12).Render("Duration"),
-		tableHeaderStyle.Width(20).Render("Timestamp"),
+		tableHeaderStyle.Width(10).Render("CPU"),
+		tableHeaderStyle.Width(12).Render("Peak RSS"),
+		tableHeaderStyle.Width(12).Render("Timestamp"),
 		tableHeaderStyle.Width(20).Render("Output"),
 	)
 	
@@ -359,20 +391,17 @@ func (m Model) renderHistoryTable() string {
 		result := history[i]
 		
 		// Command
-		cmdType := getCommandType(result.Command)
+		cmdType := result.CommandType()
 		cmdStyle := GetCommandStyle(string(cmdType))
 		
 		// Status
-		statusText := GetStatusIcon(result.Passed, false)
-		if result.Passed {
-			statusText += " Passed"
-		} else {
-			statusText += " Failed"
-		}
-		statusStyle := GetStatusStyle(result.Passed, false)
+		statusText, statusStyle := GetResultStatus(result)
 		
 		// Duration
-		duration := FormatDuration(result.Duration.Milliseconds())
+		duration := resultDuration(result)
+		
+		// Resource usage
+		cpu, rss := FormatUsage(result)
 		
 		// Timestamp
 		timestamp := result.Timestamp.Format("15:04:05")
@@ -388,10 +417,12 @@ func (m Model) renderHistoryTable() string {
 		}
 		
 		row := lipgloss.JoinHorizontal(lipgloss.Left,
-			rowStyle.Width(16).Render(cmdStyle.Render(string(cmdType))),
-			rowStyle.Width(12).Render(statusStyle.Render(statusText)),
+			rowStyle.Width(16).Render(cmdStyle.Render(cmdType.Name())),
+			rowStyle.Width(24).Render(statusStyle.Render(statusText)),
 			rowStyle.Width(12).Render(duration),
-			rowStyle.Width(20).Render(timestamp),
+			rowStyle.Width(10).Render(cpu),
+			rowStyle.Width(12).Render(rss),
+			rowStyle.Width(12).Render(timestamp),
 			rowStyle.Width(20).Render(output),
 		)
 		
@@ -457,6 +488,37 @@ func (m Model) renderDetailedLogs() string {
 	return lipgloss.JoinVertical(lipgloss.Left, logLines...)
 }
 
+// renderCommandOutput renders the most recent output lines of a command
+func (m Model) renderCommandOutput() string {
+	title := "Output: " + string(m.outputCommand)
+	if m.running[m.outputCommand] {
+		title += " (running)"
+	}
+	header := tableHeaderStyle.Render(title)
+	
+	lines := m.GetOutputLines(m.outputCommand)
+	if len(lines) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left,
+			header,
+			dimTextStyle.Render("No output yet..."),
+		)
+	}
+	
+	// Show the tail of the output that fits in the panel
+	maxVisibleLines := max(1, m.height-headerHeight-statusBarHeight-6)
+	startIdx := max(0, len(lines)-maxVisibleLines)
+	
+	rows := make([]string, 0, len(lines)-startIdx)
+	for _, line := range lines[startIdx:] {
+		rows = append(rows, normalTextStyle.Render(Truncate(line, m.width-8)))
+	}
+	
+	return lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		lipgloss.JoinVertical(lipgloss.Left, rows...),
+	)
+}
+
 // renderHelp renders the help view
 func (m Model) renderHelp() string {
 	helpText := []string{
@@ -471,6 +533,7 @@ func (m Model) renderHelp() string {
 		lipgloss.JoinHorizontal(lipgloss.Left, helpKeyStyle.Render("3"), helpDescStyle.Render("           Logs view")),
 		lipgloss.JoinHorizontal(lipgloss.Left, helpKeyStyle.Render("↑/↓"), helpDescStyle.Render("         Navigate up/down")),
 		lipgloss.JoinHorizontal(lipgloss.Left, helpKeyStyle.Render("Enter"), helpDescStyle.Render("       View details")),
+		lipgloss.JoinHorizontal(lipgloss.Left, helpKeyStyle.Render("o"), helpDescStyle.Render("           Live output of selected command")),
 		lipgloss.JoinHorizontal(lipgloss.Left, helpKeyStyle.Render("Esc"), helpDescStyle.Render("         Back to main view")),
 		"",
 		helpDescStyle.Render("COMMANDS:"),
@@ -482,6 +545,9 @@ func (m Model) renderHelp() string {
 		lipgloss.JoinHorizontal(lipgloss.Left, statusPassStyle.Render("✓"), helpDescStyle.Render("           Passed")),
 		lipgloss.JoinHorizontal(lipgloss.Left, statusFailStyle.Render("✗"), helpDescStyle.Render("           Failed")),
 		lipgloss.JoinHorizontal(lipgloss.Left, statusRunningStyle.Render("⟳"), helpDescStyle.Render("           Running")),
+		lipgloss.JoinHorizontal(lipgloss.Left, dimTextStyle.Render("⊘"), helpDescStyle.Render("           Skipped (a dependency failed)")),
+		lipgloss.JoinHorizontal(lipgloss.Left, statusFailStyle.Render("◷"), helpDescStyle.Render("           Timed out")),
+		lipgloss.JoinHorizontal(lipgloss.Left, dimTextStyle.Render("⊗"), helpDescStyle.Render("           Cancelled")),
 		"",
 		helpDescStyle.Render("The monitor watches your project files and automatically runs"),
 		helpDescStyle.Render("the configured commands when changes are detected."),